@@ -0,0 +1,55 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package assetinventory models the GCP resource hierarchy (organizations,
+// folders, and projects) used to resolve terraform IAM bindings to their
+// asset inventory parent.
+package assetinventory
+
+const (
+	// Organization is the GCP asset inventory node type for an organization.
+	Organization = "organization"
+	// Folder is the GCP asset inventory node type for a folder.
+	Folder = "folder"
+	// Project is the GCP asset inventory node type for a project.
+	Project = "project"
+)
+
+// HierarchyNode represents a single node (org, folder, or project) in the
+// GCP resource hierarchy.
+type HierarchyNode struct {
+	ID       string
+	Name     string
+	NodeType string
+}
+
+// Merge combines the given maps of hierarchy nodes, keyed by ID, into a single map.
+func Merge(nodeMaps ...map[string]*HierarchyNode) map[string]*HierarchyNode {
+	merged := make(map[string]*HierarchyNode)
+	for _, nodes := range nodeMaps {
+		for id, node := range nodes {
+			merged[id] = node
+		}
+	}
+	return merged
+}
+
+// AssetsByName re-keys the given map of hierarchy nodes, keyed by ID, to be keyed by name instead.
+func AssetsByName(nodes map[string]*HierarchyNode) map[string]*HierarchyNode {
+	byName := make(map[string]*HierarchyNode, len(nodes))
+	for _, node := range nodes {
+		byName[node.Name] = node
+	}
+	return byName
+}
@@ -0,0 +1,402 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package entrypoints implements the entrypoints command which detects the
+// terraform directories that should be planned and applied.
+package entrypoints
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/abcxyz/guardian/pkg/git"
+	"github.com/abcxyz/pkg/cli"
+)
+
+// terragruntIncludeBlock matches a terragrunt `include` block start (e.g. `include {` or
+// `include "name" {`), anchored to the start of a line so it doesn't false-positive on the
+// substring "include " appearing in a comment or elsewhere in the file.
+var terragruntIncludeBlock = regexp.MustCompile(`(?m)^\s*include(\s+"[^"]*")?\s*\{`)
+
+var _ cli.Command = (*EntrypointsCommand)(nil)
+
+const (
+	// DiscoveryModeBackend discovers entrypoints by locating a rooted backend configuration.
+	DiscoveryModeBackend = "backend"
+	// DiscoveryModeTerragrunt discovers entrypoints by locating terragrunt.hcl include blocks.
+	DiscoveryModeTerragrunt = "terragrunt"
+	// DiscoveryModeRootModule discovers entrypoints by locating any leaf directory that is a
+	// root module - one with a backend, provider, or terragrunt include block.
+	DiscoveryModeRootModule = "root-module"
+)
+
+var allowedDiscoveryModes = map[string]struct{}{
+	DiscoveryModeBackend:    {},
+	DiscoveryModeTerragrunt: {},
+	DiscoveryModeRootModule: {},
+}
+
+var allowedFormats = map[string]struct{}{
+	"text": {},
+	"json": {},
+}
+
+// EntrypointsCommand finds the terraform directories that have been modified and require plan/apply.
+type EntrypointsCommand struct {
+	cli.BaseCommand
+
+	directory string
+
+	flagIsGitHubActions   bool
+	flagGitHubOwner       string
+	flagGitHubRepo        string
+	flagPullRequestNumber int
+	flagDestRef           string
+	flagSourceRef         string
+	flagDetectChanges     bool
+	flagMaxDepth          int
+	flagFormat            string
+	flagDiscoveryMode     string
+
+	gitClient git.GitClient
+}
+
+// Desc implements cli.Command.
+func (c *EntrypointsCommand) Desc() string {
+	return "Find the terraform directories that have been modified"
+}
+
+// Help implements cli.Command.
+func (c *EntrypointsCommand) Help() string {
+	return `
+Usage: guardian entrypoints [options]
+
+  Find the terraform directories that have been modified and require a plan/apply.
+`
+}
+
+// Flags returns the set of flags understood by this command.
+func (c *EntrypointsCommand) Flags() *cli.FlagSet {
+	set := cli.NewFlagSet()
+
+	f := set.NewSection("COMMAND OPTIONS")
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "is-github-actions",
+		Target:  &c.flagIsGitHubActions,
+		Default: false,
+		Usage:   "This command is being run as a GitHub action.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "github-owner",
+		Target: &c.flagGitHubOwner,
+		Usage:  "The GitHub repository owner.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "github-repo",
+		Target: &c.flagGitHubRepo,
+		Usage:  "The GitHub repository name.",
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:   "pull-request-number",
+		Target: &c.flagPullRequestNumber,
+		Usage:  "The GitHub pull request number.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "dest-ref",
+		Target: &c.flagDestRef,
+		Usage:  "The destination ref for finding file changes.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "source-ref",
+		Target: &c.flagSourceRef,
+		Usage:  "The source ref for finding file changes.",
+	})
+
+	f.BoolVar(&cli.BoolVar{
+		Name:    "detect-changes",
+		Target:  &c.flagDetectChanges,
+		Default: false,
+		Usage:   "Restrict found entrypoint directories to those with file changes.",
+	})
+
+	f.IntVar(&cli.IntVar{
+		Name:    "max-depth",
+		Target:  &c.flagMaxDepth,
+		Default: 0,
+		Usage:   "The max directory depth to search for entrypoints, 0 means no limit.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "format",
+		Target:  &c.flagFormat,
+		Default: "text",
+		Usage:   "The output format, either text or json.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "discovery-mode",
+		Target:  &c.flagDiscoveryMode,
+		Default: DiscoveryModeBackend,
+		Usage:   "The entrypoint discovery strategy, one of backend, terragrunt, or root-module.",
+	})
+
+	set.AfterParse(func(existingErr error) (merr error) {
+		if c.flagDetectChanges && (c.flagSourceRef == "" || c.flagDestRef == "") {
+			merr = fmt.Errorf("invalid flag: source-ref and dest-ref are required to detect changes, to ignore changes set the detect-changes flag")
+			return
+		}
+
+		if _, ok := allowedFormats[c.flagFormat]; !ok {
+			merr = fmt.Errorf("invalid flag: format %s (supported formats are: [json text])", c.flagFormat)
+			return
+		}
+
+		if _, ok := allowedDiscoveryModes[c.flagDiscoveryMode]; !ok {
+			merr = fmt.Errorf("invalid flag: discovery-mode %s (supported modes are: [backend root-module terragrunt])", c.flagDiscoveryMode)
+			return
+		}
+
+		return
+	})
+
+	return set
+}
+
+// Run executes the entrypoints command.
+func (c *EntrypointsCommand) Run(ctx context.Context, args []string) error {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	c.directory = cwd
+
+	c.gitClient = git.NewGitClient(cwd)
+
+	return c.Process(ctx)
+}
+
+// Process finds the terraform entrypoint directories and writes them to stdout.
+func (c *EntrypointsCommand) Process(ctx context.Context) error {
+	if _, ok := allowedFormats[c.flagFormat]; !ok {
+		return fmt.Errorf("invalid format flag: %s", c.flagFormat)
+	}
+
+	entrypoints, err := findEntrypoints(c.directory, c.flagDiscoveryMode, c.flagMaxDepth)
+	if err != nil {
+		return fmt.Errorf("failed to find entrypoint directories: %w", err)
+	}
+
+	if c.flagDetectChanges {
+		changedDirs, err := c.gitClient.DiffDirectories(ctx, c.flagSourceRef, c.flagDestRef)
+		if err != nil {
+			return fmt.Errorf("failed to find git diff directories: %w", err)
+		}
+
+		entrypoints, err = c.withTransitiveModuleReferences(entrypoints, changedDirs)
+		if err != nil {
+			return fmt.Errorf("failed to resolve transitive module references: %w", err)
+		}
+	}
+
+	sort.Strings(entrypoints)
+
+	switch c.flagFormat {
+	case "json":
+		out, err := json.Marshal(entrypoints)
+		if err != nil {
+			return fmt.Errorf("failed to marshal entrypoints to json: %w", err)
+		}
+		fmt.Fprint(c.Stdout(), string(out))
+	default:
+		fmt.Fprint(c.Stdout(), strings.Join(entrypoints, "\n"))
+	}
+
+	return nil
+}
+
+// withTransitiveModuleReferences intersects the discovered entrypoints with the changed
+// directories, additionally including any entrypoint that locally references a shared module
+// whose files changed (e.g. `module "x" { source = "./../modules/net" }`).
+func (c *EntrypointsCommand) withTransitiveModuleReferences(entrypoints, changedDirs []string) ([]string, error) {
+	changed := make(map[string]struct{}, len(changedDirs))
+	for _, d := range changedDirs {
+		changed[filepath.Clean(d)] = struct{}{}
+	}
+
+	var result []string
+	for _, entrypoint := range entrypoints {
+		if _, ok := changed[filepath.Clean(entrypoint)]; ok {
+			result = append(result, entrypoint)
+			continue
+		}
+
+		refs, err := localModuleSources(entrypoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find module sources for %s: %w", entrypoint, err)
+		}
+
+		for _, ref := range refs {
+			resolved := filepath.Clean(filepath.Join(entrypoint, ref))
+			if _, ok := changed[resolved]; ok {
+				result = append(result, entrypoint)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// findEntrypoints walks the given root directory and returns the directories that are valid
+// terraform entrypoints for the given discovery mode.
+func findEntrypoints(root, mode string, maxDepth int) ([]string, error) {
+	var entrypoints []string
+
+	rootDepth := strings.Count(filepath.Clean(root), string(os.PathSeparator))
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if maxDepth > 0 {
+			depth := strings.Count(filepath.Clean(path), string(os.PathSeparator)) - rootDepth
+			if depth > maxDepth {
+				return filepath.SkipDir
+			}
+		}
+
+		isEntrypoint, err := isEntrypointDir(path, mode)
+		if err != nil {
+			return fmt.Errorf("failed to inspect directory %s: %w", path, err)
+		}
+		if isEntrypoint {
+			entrypoints = append(entrypoints, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", root, err)
+	}
+
+	return entrypoints, nil
+}
+
+// isEntrypointDir reports whether the given directory is a valid entrypoint for the given
+// discovery mode.
+func isEntrypointDir(dir, mode string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	hasBackend, hasProvider, hasTerragruntInclude := false, false, false
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		name := e.Name()
+		switch {
+		case name == "terragrunt.hcl":
+			contents, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return false, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			if terragruntIncludeBlock.MatchString(string(contents)) {
+				hasTerragruntInclude = true
+			}
+		case strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json"):
+			contents, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return false, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			text := string(contents)
+			if strings.Contains(text, "backend \"") || strings.Contains(text, `"backend":`) {
+				hasBackend = true
+			}
+			if strings.Contains(text, "provider \"") || strings.Contains(text, `"provider":`) {
+				hasProvider = true
+			}
+		}
+	}
+
+	switch mode {
+	case DiscoveryModeTerragrunt:
+		return hasTerragruntInclude, nil
+	case DiscoveryModeRootModule:
+		return hasBackend || hasProvider || hasTerragruntInclude, nil
+	default:
+		return hasBackend, nil
+	}
+}
+
+// localModuleSources returns the local `source` values of any `module` blocks declared in the
+// given directory's terraform files (e.g. `module "x" { source = "./modules/net" }`).
+func localModuleSources(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var sources []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".tf") {
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", e.Name(), err)
+		}
+
+		for _, line := range strings.Split(string(contents), "\n") {
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, "source") {
+				continue
+			}
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			source := strings.Trim(strings.TrimSpace(parts[1]), `"`)
+			if strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	return sources, nil
+}
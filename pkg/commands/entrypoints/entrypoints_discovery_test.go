@@ -0,0 +1,158 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entrypoints
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/abcxyz/guardian/pkg/git"
+	"github.com/abcxyz/pkg/logging"
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestIsEntrypointDir(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		dir  string
+		mode string
+		exp  bool
+	}{
+		{name: "backend_mode_finds_backend", dir: "testdata/discovery/backend-only", mode: DiscoveryModeBackend, exp: true},
+		{name: "backend_mode_ignores_provider_only", dir: "testdata/discovery/provider-only", mode: DiscoveryModeBackend, exp: false},
+		{name: "terragrunt_mode_finds_include", dir: "testdata/discovery/terragrunt-only", mode: DiscoveryModeTerragrunt, exp: true},
+		{name: "terragrunt_mode_ignores_backend_only", dir: "testdata/discovery/backend-only", mode: DiscoveryModeTerragrunt, exp: false},
+		{name: "root_module_mode_finds_backend", dir: "testdata/discovery/backend-only", mode: DiscoveryModeRootModule, exp: true},
+		{name: "root_module_mode_finds_provider", dir: "testdata/discovery/provider-only", mode: DiscoveryModeRootModule, exp: true},
+		{name: "root_module_mode_finds_terragrunt", dir: "testdata/discovery/terragrunt-only", mode: DiscoveryModeRootModule, exp: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := isEntrypointDir(tc.dir, tc.mode)
+			if err != nil {
+				t.Fatalf("isEntrypointDir(%q, %q) unexpected error: %v", tc.dir, tc.mode, err)
+			}
+			if got != tc.exp {
+				t.Errorf("isEntrypointDir(%q, %q) = %v, want %v", tc.dir, tc.mode, got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestEntrypointsProcess_DiscoveryMode(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(context.Background(), logging.TestLogger(t))
+
+	cases := []struct {
+		name              string
+		flagDiscoveryMode string
+		expStdout         []string
+	}{
+		{
+			name:              "root_module_finds_all_entrypoint_kinds",
+			flagDiscoveryMode: DiscoveryModeRootModule,
+			expStdout: []string{
+				"testdata/discovery/backend-only",
+				"testdata/discovery/provider-only",
+				"testdata/discovery/terragrunt-only",
+			},
+		},
+		{
+			name:              "terragrunt_finds_only_include_blocks",
+			flagDiscoveryMode: DiscoveryModeTerragrunt,
+			expStdout: []string{
+				"testdata/discovery/terragrunt-only",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &EntrypointsCommand{
+				directory:         "testdata/discovery",
+				flagFormat:        "text",
+				flagDiscoveryMode: tc.flagDiscoveryMode,
+			}
+
+			_, stdout, stderr := c.Pipe()
+
+			if err := c.Process(ctx); err != nil {
+				t.Fatalf("Process() unexpected error: %v", err)
+			}
+			if stderr.String() != "" {
+				t.Errorf("unexpected stderr: %s", stderr.String())
+			}
+
+			got := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+			if len(got) != len(tc.expStdout) {
+				t.Fatalf("got entrypoints %v, want %v", got, tc.expStdout)
+			}
+			for _, want := range tc.expStdout {
+				found := false
+				for _, g := range got {
+					if g == want {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected entrypoints %v to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEntrypointsProcess_TransitiveModuleReferences(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(context.Background(), logging.TestLogger(t))
+
+	c := &EntrypointsCommand{
+		directory:         "testdata/transitive",
+		flagFormat:        "text",
+		flagDestRef:       "main",
+		flagSourceRef:     "ldap/feature",
+		flagDetectChanges: true,
+		gitClient: &git.MockGitClient{
+			// Only the shared module changed, not the app entrypoint itself - app should still
+			// be included because it locally references the module via a relative source.
+			DiffResp: []string{
+				"testdata/transitive/modules/net",
+			},
+		},
+	}
+
+	_, stdout, _ := c.Pipe()
+
+	err := c.Process(ctx)
+	if diff := testutil.DiffErrString(err, ""); diff != "" {
+		t.Errorf(diff)
+	}
+
+	want := "testdata/transitive/app"
+	if got := strings.TrimSpace(stdout.String()); got != want {
+		t.Errorf("Process() stdout = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plan implements the plan command and its supporting configuration.
+package plan
+
+import (
+	"fmt"
+
+	"github.com/abcxyz/guardian/pkg/vcs"
+)
+
+// Config is the set of pull/merge-request facts Guardian needs to comment on and react to the
+// run that triggered it, mapped from whichever CI provider (GitHub Actions, GitLab CI, Gitea
+// Actions) is currently running.
+type Config struct {
+	IsAction          bool
+	EventName         string
+	RepositoryOwner   string
+	RepositoryName    string
+	PullRequestNumber int
+	ServerURL         string
+	RunID             int64
+	RunAttempt        int64
+}
+
+// MapVCSContext detects the current CI provider from env and populates c from its pull/merge
+// request context.
+func (c *Config) MapVCSContext(env map[string]string) error {
+	provider, err := vcs.Detect(env)
+	if err != nil {
+		return fmt.Errorf("failed to detect vcs provider: %w", err)
+	}
+
+	prCtx, err := provider.MapContext(env)
+	if err != nil {
+		return fmt.Errorf("failed to map vcs context: %w", err)
+	}
+
+	c.IsAction = true
+	c.EventName = prCtx.EventName
+	c.RepositoryOwner = prCtx.Owner
+	c.RepositoryName = prCtx.Repo
+	c.PullRequestNumber = prCtx.PullRequestNumber
+	c.ServerURL = prCtx.ServerURL
+	c.RunID = prCtx.RunID
+	c.RunAttempt = prCtx.RunAttempt
+
+	return nil
+}
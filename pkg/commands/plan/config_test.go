@@ -15,35 +15,50 @@
 package plan
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/abcxyz/pkg/testutil"
 	"github.com/google/go-cmp/cmp"
-	"github.com/sethvargo/go-githubactions"
 )
 
-func TestConfig_MapGitHubContext(t *testing.T) {
+func writeGitHubEventFile(t *testing.T, event map[string]any) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "event.json")
+	b, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfig_MapVCSContext_GitHubActions(t *testing.T) {
 	t.Parallel()
 
 	cases := []struct {
-		name          string
-		githubContext *githubactions.GitHubContext
-		exp           *Config
-		wantErr       string
+		name    string
+		event   map[string]any
+		env     map[string]string
+		exp     *Config
+		wantErr string
 	}{
 		{
-			name: "success",
-			githubContext: &githubactions.GitHubContext{
-				Actions:         true,
-				EventName:       "pull_request",
-				Repository:      "github/repository",
-				RepositoryOwner: "owner",
-				ServerURL:       "https://github.com",
-				RunID:           int64(100),
-				RunAttempt:      int64(1),
-				Event: map[string]any{
-					"number": float64(100),
-				},
+			name:  "success",
+			event: map[string]any{"number": float64(100)},
+			env: map[string]string{
+				"GITHUB_ACTIONS":          "true",
+				"GITHUB_EVENT_NAME":       "pull_request",
+				"GITHUB_REPOSITORY":       "github/repository",
+				"GITHUB_REPOSITORY_OWNER": "owner",
+				"GITHUB_SERVER_URL":       "https://github.com",
+				"GITHUB_RUN_ID":           "100",
+				"GITHUB_RUN_ATTEMPT":      "1",
 			},
 			exp: &Config{
 				IsAction:          true,
@@ -57,18 +72,18 @@ func TestConfig_MapGitHubContext(t *testing.T) {
 			},
 		},
 		{
-			name: "missing_event_fields",
-			githubContext: &githubactions.GitHubContext{
-				Actions:         true,
-				BaseRef:         "main",
-				EventName:       "pull_request",
-				HeadRef:         "pr-branch",
-				Repository:      "github/repository",
-				RepositoryOwner: "owner",
-				Event:           map[string]any{},
-				ServerURL:       "https://github.com",
-				RunID:           int64(100),
-				RunAttempt:      int64(1),
+			name:  "missing_event_fields",
+			event: map[string]any{},
+			env: map[string]string{
+				"GITHUB_ACTIONS":          "true",
+				"GITHUB_BASE_REF":         "main",
+				"GITHUB_EVENT_NAME":       "pull_request",
+				"GITHUB_HEAD_REF":         "pr-branch",
+				"GITHUB_REPOSITORY":       "github/repository",
+				"GITHUB_REPOSITORY_OWNER": "owner",
+				"GITHUB_SERVER_URL":       "https://github.com",
+				"GITHUB_RUN_ID":           "100",
+				"GITHUB_RUN_ATTEMPT":      "1",
 			},
 			wantErr: "failed to get pull request number from github event",
 		},
@@ -80,9 +95,15 @@ func TestConfig_MapGitHubContext(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
+			env := make(map[string]string, len(tc.env)+1)
+			for k, v := range tc.env {
+				env[k] = v
+			}
+			env["GITHUB_EVENT_PATH"] = writeGitHubEventFile(t, tc.event)
+
 			c := &Config{}
 
-			err := c.MapGitHubContext(tc.githubContext)
+			err := c.MapVCSContext(env)
 			if err != nil || tc.wantErr != "" {
 				if diff := testutil.DiffErrString(err, tc.wantErr); diff != "" {
 					t.Fatal(diff)
@@ -95,4 +116,4 @@ func TestConfig_MapGitHubContext(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
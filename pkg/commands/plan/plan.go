@@ -0,0 +1,221 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abcxyz/guardian/pkg/iam"
+	"github.com/abcxyz/guardian/pkg/policy"
+	"github.com/abcxyz/guardian/pkg/terraform/parser"
+	"github.com/abcxyz/guardian/pkg/vcs"
+	"github.com/abcxyz/pkg/cli"
+)
+
+var _ cli.Command = (*PlanCommand)(nil)
+
+// planParser finds the IAM bindings proposed by a set of terraform plans. Satisfied by
+// *parser.PlanParser; narrowed to an interface here so tests can inject a fake.
+type planParser interface {
+	ProcessPlans(ctx context.Context, planURIs []string) ([]*iam.AssetIAM, error)
+}
+
+// policyEvaluator evaluates IAM bindings against a policy bundle. Satisfied by
+// *policy.Evaluator; narrowed to an interface here so tests can inject a fake.
+type policyEvaluator interface {
+	Evaluate(ctx context.Context, bindings []*iam.AssetIAM) ([]*policy.Finding, error)
+}
+
+// PlanCommand finds the IAM bindings proposed by a terraform plan, evaluates them against a
+// Rego policy bundle, and posts any findings as a pull/merge request comment.
+type PlanCommand struct {
+	cli.BaseCommand
+
+	flagOrganizationID string
+	flagPlanURIs       string
+	flagPolicyDir      string
+	flagSARIFOutput    string
+
+	config *Config
+
+	// planParser and evaluator are left nil in production and lazily constructed in Process;
+	// tests set them directly to avoid needing real GCP/OPA dependencies. vcsProvider is the
+	// same, but also skips the real vcs.Detect call entirely when set.
+	planParser  planParser
+	evaluator   policyEvaluator
+	vcsProvider vcs.Provider
+}
+
+// Desc implements cli.Command.
+func (c *PlanCommand) Desc() string {
+	return "Evaluate a terraform plan's proposed IAM changes against policy"
+}
+
+// Help implements cli.Command.
+func (c *PlanCommand) Help() string {
+	return `
+Usage: guardian plan [options]
+
+  Evaluate the IAM bindings proposed by a terraform plan against a policy bundle and post any
+  findings as a pull/merge request comment.
+`
+}
+
+// Flags returns the set of flags understood by this command.
+func (c *PlanCommand) Flags() *cli.FlagSet {
+	set := cli.NewFlagSet()
+
+	f := set.NewSection("COMMAND OPTIONS")
+
+	f.StringVar(&cli.StringVar{
+		Name:   "organization-id",
+		Target: &c.flagOrganizationID,
+		Usage:  "The GCP organization ID the plan's resources belong to.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:   "plan-uris",
+		Target: &c.flagPlanURIs,
+		Usage:  "A comma-separated list of `terraform show -json` plan output URIs to evaluate.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "policy-dir",
+		Target:  &c.flagPolicyDir,
+		Default: "",
+		Usage:   "A directory of Rego policies to evaluate bindings against. Defaults to Guardian's built-in policy bundle.",
+	})
+
+	f.StringVar(&cli.StringVar{
+		Name:    "sarif-output",
+		Target:  &c.flagSARIFOutput,
+		Default: "",
+		Usage:   "A file path to write findings to as a SARIF log, for GitHub code scanning. If unset, no SARIF log is written.",
+	})
+
+	return set
+}
+
+// Run executes the plan command.
+func (c *PlanCommand) Run(ctx context.Context, args []string) error {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := cfg.MapVCSContext(envMap(os.Environ())); err != nil {
+		return fmt.Errorf("failed to map vcs context: %w", err)
+	}
+	c.config = cfg
+
+	return c.Process(ctx)
+}
+
+// Process parses the configured plan files, evaluates their proposed IAM bindings against
+// policy, and posts any findings as a pull/merge request comment.
+func (c *PlanCommand) Process(ctx context.Context) error {
+	planParser := c.planParser
+	if planParser == nil {
+		pp, err := parser.NewPlanParser(ctx, c.flagOrganizationID)
+		if err != nil {
+			return fmt.Errorf("failed to create terraform plan parser: %w", err)
+		}
+		planParser = pp
+	}
+
+	bindings, err := planParser.ProcessPlans(ctx, splitCSV(c.flagPlanURIs))
+	if err != nil {
+		return fmt.Errorf("failed to process terraform plans: %w", err)
+	}
+
+	evaluator := c.evaluator
+	if evaluator == nil {
+		ev, err := policy.NewEvaluator(ctx, c.flagPolicyDir)
+		if err != nil {
+			return fmt.Errorf("failed to create policy evaluator: %w", err)
+		}
+		evaluator = ev
+	}
+
+	findings, err := evaluator.Evaluate(ctx, bindings)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policy: %w", err)
+	}
+
+	if c.flagSARIFOutput != "" {
+		if err := policy.WriteSARIFFile(c.flagSARIFOutput, findings); err != nil {
+			return fmt.Errorf("failed to write sarif output: %w", err)
+		}
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+
+	// Findings exist, so post them as a PR comment via whichever provider's PostComment is
+	// wired up for the CI environment we're running in (see pkg/vcs).
+	provider := c.vcsProvider
+	if provider == nil {
+		p, err := vcs.Detect(envMap(os.Environ()))
+		if err != nil {
+			return fmt.Errorf("failed to detect vcs provider: %w", err)
+		}
+		provider = p
+	}
+
+	prCtx := &vcs.PRContext{
+		Owner:             c.config.RepositoryOwner,
+		Repo:              c.config.RepositoryName,
+		PullRequestNumber: c.config.PullRequestNumber,
+		ServerURL:         c.config.ServerURL,
+	}
+
+	if err := provider.PostComment(ctx, prCtx, policy.FormatComment(findings)); err != nil {
+		return fmt.Errorf("failed to post policy findings comment: %w", err)
+	}
+
+	return nil
+}
+
+// splitCSV splits a comma-separated flag value into its non-empty entries.
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// envMap converts `KEY=VALUE` entries, as returned by os.Environ, into a map.
+func envMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if ok {
+			env[k] = v
+		}
+	}
+	return env
+}
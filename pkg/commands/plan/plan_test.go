@@ -0,0 +1,152 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abcxyz/guardian/pkg/iam"
+	"github.com/abcxyz/guardian/pkg/policy"
+	"github.com/abcxyz/guardian/pkg/vcs"
+	"github.com/abcxyz/pkg/logging"
+)
+
+// fakePlanParser is a planParser that returns a fixed set of bindings.
+type fakePlanParser struct {
+	bindings []*iam.AssetIAM
+	err      error
+}
+
+func (f *fakePlanParser) ProcessPlans(ctx context.Context, planURIs []string) ([]*iam.AssetIAM, error) {
+	return f.bindings, f.err
+}
+
+// fakeEvaluator is a policyEvaluator that returns a fixed set of findings.
+type fakeEvaluator struct {
+	findings []*policy.Finding
+	err      error
+}
+
+func (f *fakeEvaluator) Evaluate(ctx context.Context, bindings []*iam.AssetIAM) ([]*policy.Finding, error) {
+	return f.findings, f.err
+}
+
+// fakeProvider is a vcs.Provider that records the PRContext and body passed to PostComment.
+type fakeProvider struct {
+	gotPRCtx *vcs.PRContext
+	gotBody  string
+	err      error
+}
+
+func (f *fakeProvider) MapContext(env map[string]string) (*vcs.PRContext, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) PostComment(ctx context.Context, prCtx *vcs.PRContext, body string) error {
+	f.gotPRCtx = prCtx
+	f.gotBody = body
+	return f.err
+}
+
+func (f *fakeProvider) ListChangedFiles(ctx context.Context, prCtx *vcs.PRContext) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) GetMergeBase(ctx context.Context, prCtx *vcs.PRContext) (string, error) {
+	return "", nil
+}
+
+func TestPlanCommand_Process(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithLogger(context.Background(), logging.TestLogger(t))
+
+	finding := &policy.Finding{
+		Severity: "HIGH",
+		RuleID:   "no-owner-role",
+		Resource: "projects/my-project",
+		Member:   "user:a@example.com",
+		Role:     "roles/owner",
+		Message:  "roles/owner should not be granted directly",
+	}
+
+	cases := []struct {
+		name         string
+		findings     []*policy.Finding
+		expPostCount int
+	}{
+		{
+			name:         "findings_present_posts_comment",
+			findings:     []*policy.Finding{finding},
+			expPostCount: 1,
+		},
+		{
+			name:         "no_findings_skips_comment",
+			findings:     nil,
+			expPostCount: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &fakeProvider{}
+
+			c := &PlanCommand{
+				config: &Config{
+					RepositoryOwner:   "my-org",
+					RepositoryName:    "my-repo",
+					PullRequestNumber: 42,
+					ServerURL:         "https://gitea.example.com",
+				},
+				planParser:  &fakePlanParser{bindings: []*iam.AssetIAM{{Member: "user:a@example.com", Role: "roles/owner"}}},
+				evaluator:   &fakeEvaluator{findings: tc.findings},
+				vcsProvider: provider,
+			}
+
+			if err := c.Process(ctx); err != nil {
+				t.Fatalf("Process() unexpected error: %v", err)
+			}
+
+			if tc.expPostCount == 0 {
+				if provider.gotPRCtx != nil {
+					t.Fatalf("PostComment was called, want no call")
+				}
+				return
+			}
+
+			if provider.gotPRCtx == nil {
+				t.Fatalf("PostComment was not called, want a call")
+			}
+
+			// The PRContext must carry ServerURL through from config - without it, Gitea and
+			// self-hosted GitLab comment requests have no host to target.
+			exp := &vcs.PRContext{
+				Owner:             "my-org",
+				Repo:              "my-repo",
+				PullRequestNumber: 42,
+				ServerURL:         "https://gitea.example.com",
+			}
+			if *provider.gotPRCtx != *exp {
+				t.Errorf("PostComment prCtx = %+v, want %+v", provider.gotPRCtx, exp)
+			}
+			if provider.gotBody == "" {
+				t.Error("PostComment body is empty, want findings rendered into the comment")
+			}
+		})
+	}
+}
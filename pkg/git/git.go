@@ -0,0 +1,81 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package git provides the functionality to interact with a local git
+// repository via the git CLI.
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GitClient defines the functionality needed to diff a local git repository.
+type GitClient interface {
+	// DiffDirectories returns the absolute paths of directories containing
+	// files that changed between sourceRef and destRef.
+	DiffDirectories(ctx context.Context, sourceRef, destRef string) ([]string, error)
+}
+
+// GitExecClient implements the GitClient interface by shelling out to the git CLI.
+type GitExecClient struct {
+	WorkingDir string
+}
+
+// NewGitClient creates a new GitClient rooted at the given working directory.
+func NewGitClient(workingDir string) *GitExecClient {
+	return &GitExecClient{WorkingDir: workingDir}
+}
+
+// DiffDirectories returns the distinct directories containing files changed
+// between sourceRef and destRef, using `git diff --name-only`.
+func (g *GitExecClient) DiffDirectories(ctx context.Context, sourceRef, destRef string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", fmt.Sprintf("%s...%s", destRef, sourceRef))
+	cmd.Dir = g.WorkingDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run git diff: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	var dirs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		dir := g.WorkingDir + "/" + line[:strings.LastIndex(line, "/")+1]
+		if _, ok := seen[dir]; ok {
+			continue
+		}
+		seen[dir] = struct{}{}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// MockGitClient is a test double for GitClient.
+type MockGitClient struct {
+	DiffResp []string
+	DiffErr  error
+}
+
+// DiffDirectories returns the configured mock response.
+func (m *MockGitClient) DiffDirectories(ctx context.Context, sourceRef, destRef string) ([]string, error) {
+	if m.DiffErr != nil {
+		return nil, m.DiffErr
+	}
+	return m.DiffResp, nil
+}
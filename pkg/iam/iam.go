@@ -0,0 +1,52 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package iam contains the types used to represent GCP IAM bindings
+// discovered from terraform state and plan files.
+package iam
+
+// AssetIAM represents a single IAM grant for a GCP resource.
+type AssetIAM struct {
+	Member       string `json:"member"`
+	Role         string `json:"role"`
+	ResourceID   string `json:"resource_id"`
+	ResourceType string `json:"resource_type"`
+	// Action describes how this grant was observed to be changing, one of
+	// "create", "update", or "delete". It is only populated when the binding
+	// was discovered from a terraform plan rather than a state file.
+	Action string `json:"action,omitempty"`
+	// Condition is the IAM condition attached to this grant, if any. A nil
+	// Condition means the binding is unconditional.
+	Condition *Condition `json:"condition,omitempty"`
+}
+
+// Condition represents a CEL IAM condition attached to a binding.
+type Condition struct {
+	Title       string `json:"title"`
+	Expression  string `json:"expression"`
+	Description string `json:"description,omitempty"`
+}
+
+// Policy represents a GCP IAM policy document, as stored in the `policy_data`
+// attribute of an authoritative `google_*_iam_policy` terraform resource.
+type Policy struct {
+	Bindings []PolicyBinding `json:"bindings"`
+}
+
+// PolicyBinding represents a single role/members/condition entry in a Policy.
+type PolicyBinding struct {
+	Role      string     `json:"role"`
+	Members   []string   `json:"members"`
+	Condition *Condition `json:"condition,omitempty"`
+}
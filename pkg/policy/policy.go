@@ -0,0 +1,162 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy evaluates terraform-discovered IAM bindings against a bundle of Rego
+// policies, surfacing violations as structured findings.
+package policy
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/abcxyz/guardian/pkg/iam"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+//go:embed bundle
+var defaultBundle embed.FS
+
+// denyQuery is the Rego query Guardian's bundle convention expects every policy package to
+// satisfy: a `deny` set of finding objects.
+const denyQuery = "data.guardian.deny"
+
+// Finding is a single policy violation surfaced for an IAM binding.
+type Finding struct {
+	Severity string `json:"severity"`
+	RuleID   string `json:"rule_id"`
+	Resource string `json:"resource"`
+	Member   string `json:"member"`
+	Role     string `json:"role"`
+	Message  string `json:"message"`
+}
+
+// Evaluator evaluates IAM bindings against a prepared Rego policy bundle.
+type Evaluator struct {
+	query rego.PreparedEvalQuery
+}
+
+// NewEvaluator prepares an Evaluator from the Rego bundle at policyDir. When policyDir is
+// empty, Guardian's built-in default bundle is used instead.
+func NewEvaluator(ctx context.Context, policyDir string) (*Evaluator, error) {
+	opts := []func(*rego.Rego){rego.Query(denyQuery)}
+
+	if policyDir == "" {
+		moduleOpts, err := embeddedModules(defaultBundle, "bundle")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load default policy bundle: %w", err)
+		}
+		opts = append(opts, moduleOpts...)
+	} else {
+		opts = append(opts, rego.Load([]string{policyDir}, nil))
+	}
+
+	query, err := rego.New(opts...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare policy bundle: %w", err)
+	}
+
+	return &Evaluator{query: query}, nil
+}
+
+// embeddedModules returns a rego.Module option for every .rego file under root in fsys.
+func embeddedModules(fsys fs.FS, root string) ([]func(*rego.Rego), error) {
+	var opts []func(*rego.Rego)
+	err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		contents, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("failed to read embedded policy %s: %w", path, err)
+		}
+		opts = append(opts, rego.Module(path, string(contents)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk embedded policy bundle: %w", err)
+	}
+	return opts, nil
+}
+
+// Evaluate runs every binding in bindings through the policy bundle and returns the findings
+// produced, in the order the bindings were given.
+func (e *Evaluator) Evaluate(ctx context.Context, bindings []*iam.AssetIAM) ([]*Finding, error) {
+	var findings []*Finding
+	for _, b := range bindings {
+		bindingFindings, err := e.evaluateBinding(ctx, b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate binding %s/%s on %s: %w", b.Member, b.Role, b.ResourceID, err)
+		}
+		findings = append(findings, bindingFindings...)
+	}
+	return findings, nil
+}
+
+func (e *Evaluator) evaluateBinding(ctx context.Context, b *iam.AssetIAM) ([]*Finding, error) {
+	input := map[string]any{
+		"member":        b.Member,
+		"role":          b.Role,
+		"resource_id":   b.ResourceID,
+		"resource_type": b.ResourceType,
+	}
+
+	resultSet, err := e.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate rego query: %w", err)
+	}
+
+	var findings []*Finding
+	for _, result := range resultSet {
+		for _, expr := range result.Expressions {
+			denials, ok := expr.Value.([]any)
+			if !ok {
+				continue
+			}
+			for _, d := range denials {
+				finding, err := toFinding(d)
+				if err != nil {
+					return nil, err
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings, nil
+}
+
+func toFinding(v any) (*Finding, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("policy deny rule returned a non-object finding: %#v", v)
+	}
+
+	return &Finding{
+		Severity: stringField(m, "severity"),
+		RuleID:   stringField(m, "rule_id"),
+		Resource: stringField(m, "resource"),
+		Member:   stringField(m, "member"),
+		Role:     stringField(m, "role"),
+		Message:  stringField(m, "message"),
+	}, nil
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
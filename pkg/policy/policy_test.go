@@ -0,0 +1,115 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abcxyz/guardian/pkg/iam"
+)
+
+func TestEvaluator_Evaluate_DefaultBundle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	evaluator, err := NewEvaluator(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name       string
+		binding    *iam.AssetIAM
+		wantRuleID string
+	}{
+		{
+			name: "org_owner_to_user_denied",
+			binding: &iam.AssetIAM{
+				Member:       "user:alice@example.com",
+				Role:         "roles/owner",
+				ResourceID:   "org-1",
+				ResourceType: "organization",
+			},
+			wantRuleID: "no-org-owner-to-non-group",
+		},
+		{
+			name: "org_owner_to_group_allowed",
+			binding: &iam.AssetIAM{
+				Member:       "group:admins@example.com",
+				Role:         "roles/owner",
+				ResourceID:   "org-1",
+				ResourceType: "organization",
+			},
+		},
+		{
+			name: "public_principal_on_project_denied",
+			binding: &iam.AssetIAM{
+				Member:       "allUsers",
+				Role:         "roles/viewer",
+				ResourceID:   "project-1",
+				ResourceType: "project",
+			},
+			wantRuleID: "no-public-principal-on-project",
+		},
+		{
+			name: "sa_impersonation_on_folder_denied",
+			binding: &iam.AssetIAM{
+				Member:       "serviceAccount:sa@example.iam.gserviceaccount.com",
+				Role:         "roles/iam.serviceAccountTokenCreator",
+				ResourceID:   "folder-1",
+				ResourceType: "folder",
+			},
+			wantRuleID: "no-cross-folder-sa-impersonation",
+		},
+		{
+			name: "unrelated_binding_allowed",
+			binding: &iam.AssetIAM{
+				Member:       "user:alice@example.com",
+				Role:         "roles/viewer",
+				ResourceID:   "project-1",
+				ResourceType: "project",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			findings, err := evaluator.Evaluate(ctx, []*iam.AssetIAM{tc.binding})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.wantRuleID == "" {
+				if len(findings) != 0 {
+					t.Errorf("got %d findings, want 0: %#v", len(findings), findings)
+				}
+				return
+			}
+
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1: %#v", len(findings), findings)
+			}
+			if got := findings[0].RuleID; got != tc.wantRuleID {
+				t.Errorf("got rule_id %q, want %q", got, tc.wantRuleID)
+			}
+		})
+	}
+}
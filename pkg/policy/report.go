@@ -0,0 +1,52 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FormatComment renders findings as a markdown pull/merge request comment body.
+func FormatComment(findings []*Finding) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "### :warning: Guardian found %d IAM policy violation(s)\n\n", len(findings))
+	fmt.Fprint(&b, "| Severity | Rule | Resource | Member | Role | Message |\n")
+	fmt.Fprint(&b, "| --- | --- | --- | --- | --- | --- |\n")
+
+	for _, f := range findings {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			f.Severity, f.RuleID, f.Resource, f.Member, f.Role, f.Message)
+	}
+
+	return b.String()
+}
+
+// WriteSARIFFile writes findings to path as a SARIF log.
+func WriteSARIFFile(path string, findings []*Finding) error {
+	b, err := json.MarshalIndent(ToSARIF(findings), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sarif log: %w", err)
+	}
+
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write sarif file %s: %w", path, err)
+	}
+
+	return nil
+}
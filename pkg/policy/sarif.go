@@ -0,0 +1,134 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+// sarifVersion is the SARIF schema version Guardian emits, matching what GitHub code scanning
+// currently accepts.
+const sarifVersion = "2.1.0"
+
+const sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// SARIFLog is the top-level SARIF document produced by ToSARIF.
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single analysis run within a SARIF document.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool that produced the run's results.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver identifies Guardian as the analysis tool and lists the rules it can report.
+type SARIFDriver struct {
+	Name  string      `json:"name"`
+	Rules []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes one policy rule that may appear in Results.
+type SARIFRule struct {
+	ID string `json:"id"`
+}
+
+// SARIFResult is a single finding, mapped to the SARIF result shape.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFMessage is the human-readable explanation of a SARIFResult.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation identifies the resource a SARIFResult applies to. Guardian findings are about
+// IAM bindings rather than source files, so PhysicalLocation is omitted and the resource name is
+// carried in LogicalLocations instead.
+type SARIFLocation struct {
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations"`
+}
+
+// SARIFLogicalLocation names the GCP resource a finding applies to.
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// ToSARIF converts findings into a SARIF log that GitHub code scanning (and other SARIF
+// consumers) can ingest.
+func ToSARIF(findings []*Finding) *SARIFLog {
+	ruleIDs := make(map[string]struct{})
+	var rules []SARIFRule
+	var results []SARIFResult
+
+	for _, f := range findings {
+		if _, ok := ruleIDs[f.RuleID]; !ok {
+			ruleIDs[f.RuleID] = struct{}{}
+			rules = append(rules, SARIFRule{ID: f.RuleID})
+		}
+
+		results = append(results, SARIFResult{
+			RuleID: f.RuleID,
+			Level:  sarifLevel(f.Severity),
+			Message: SARIFMessage{
+				Text: f.Message,
+			},
+			Locations: []SARIFLocation{
+				{
+					LogicalLocations: []SARIFLogicalLocation{
+						{FullyQualifiedName: f.Resource},
+					},
+				},
+			},
+		})
+	}
+
+	return &SARIFLog{
+		Version: sarifVersion,
+		Schema:  sarifSchemaURI,
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{
+					Driver: SARIFDriver{
+						Name:  "guardian",
+						Rules: rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+// sarifLevel maps a Finding severity to the SARIF result level vocabulary (none, note, warning,
+// error).
+func sarifLevel(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH":
+		return "error"
+	case "MEDIUM":
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// NewStateBackend constructs the StateBackend implementation indicated by the scheme of
+// backendURI (e.g. "gs://", "s3://", "azurerm://", "tfc://", "file://"). Buckets, containers,
+// or workspaces are supplied later to ObjectsWithName (via StateFileURIs), scoped per state lookup.
+func NewStateBackend(ctx context.Context, backendURI string) (StateBackend, error) {
+	switch Scheme(backendURI) {
+	case "", "gs":
+		return NewGoogleCloudStorage(ctx)
+	case "s3":
+		return NewS3Storage(ctx)
+	case "azurerm":
+		return NewAzureBlobStorage(ctx)
+	case "tfc", "hcp":
+		return NewTerraformCloudStorage(ctx, backendURI)
+	case "file":
+		return NewLocalStorage(strings.TrimPrefix(backendURI, "file://")), nil
+	default:
+		return nil, fmt.Errorf("unsupported state backend URI: %s", backendURI)
+	}
+}
+
+// S3Storage implements StateBackend for terraform state stored in AWS S3. Guardian only reads
+// state, so it never needs to take or check the DynamoDB lock some S3 backends are configured
+// with.
+type S3Storage struct {
+	client *s3.Client
+}
+
+// NewS3Storage creates a new S3Storage client using the default AWS credential chain.
+func NewS3Storage(ctx context.Context) (*S3Storage, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &S3Storage{
+		client: s3.NewFromConfig(cfg),
+	}, nil
+}
+
+// ObjectsWithName returns the s3:// URIs of all objects in bucket whose key matches objectName.
+func (s *S3Storage) ObjectsWithName(ctx context.Context, bucket, objectName string) ([]string, error) {
+	var uris []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{Bucket: &bucket})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects in bucket %s: %w", bucket, err)
+		}
+		for _, obj := range page.Contents {
+			if obj.Key != nil && strings.HasSuffix(*obj.Key, objectName) {
+				uris = append(uris, fmt.Sprintf("s3://%s/%s", bucket, *obj.Key))
+			}
+		}
+	}
+	return uris, nil
+}
+
+// DownloadObjectURI downloads the object at an s3:// URI previously returned by ObjectsWithName.
+func (s *S3Storage) DownloadObjectURI(ctx context.Context, uri string) (io.ReadCloser, error) {
+	bucket, name, err := SplitObjectURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 URI: %w", err)
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: bucket, Key: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object %s/%s: %w", *bucket, *name, err)
+	}
+	return out.Body, nil
+}
+
+// AzureBlobStorage implements StateBackend for terraform state stored in an Azure Storage
+// account ("AzureRM" remote state). The bucket parameter used by ObjectsWithName is interpreted
+// as "<account>/<container>"; DownloadObjectURI instead parses the full azurerm:// URI that
+// ObjectsWithName returned, since it additionally carries the blob name.
+type AzureBlobStorage struct{}
+
+// NewAzureBlobStorage creates a new AzureBlobStorage client using the default Azure credential chain.
+func NewAzureBlobStorage(ctx context.Context) (*AzureBlobStorage, error) {
+	return &AzureBlobStorage{}, nil
+}
+
+// ObjectsWithName returns the azurerm:// URIs of all blobs in "<account>/<container>" whose name
+// matches objectName.
+func (a *AzureBlobStorage) ObjectsWithName(ctx context.Context, accountAndContainer, objectName string) ([]string, error) {
+	account, container, err := splitAccountAndContainer(accountAndContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.clientFor(account)
+	if err != nil {
+		return nil, err
+	}
+
+	var uris []string
+	pager := client.NewListBlobsFlatPager(container, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blobs in container %s/%s: %w", account, container, err)
+		}
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name != nil && strings.HasSuffix(*blob.Name, objectName) {
+				uris = append(uris, fmt.Sprintf("azurerm://%s/%s/%s", account, container, *blob.Name))
+			}
+		}
+	}
+	return uris, nil
+}
+
+// DownloadObjectURI downloads the blob at an azurerm://<account>/<container>/<blob> URI
+// previously returned by ObjectsWithName.
+func (a *AzureBlobStorage) DownloadObjectURI(ctx context.Context, uri string) (io.ReadCloser, error) {
+	account, container, name, err := splitAzureBlobURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.clientFor(account)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.DownloadStream(ctx, container, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob %s/%s/%s: %w", account, container, name, err)
+	}
+	return resp.Body, nil
+}
+
+func (a *AzureBlobStorage) clientFor(account string) (*azblob.Client, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default azure credential: %w", err)
+	}
+
+	client, err := azblob.NewClient(fmt.Sprintf("https://%s.blob.core.windows.net/", account), cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure blob client for account %s: %w", account, err)
+	}
+	return client, nil
+}
+
+func splitAccountAndContainer(s string) (string, string, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid azurerm backend location, want <account>/<container>, got: %s", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// splitAzureBlobURI splits an azurerm://<account>/<container>/<blob> URI, as emitted by
+// AzureBlobStorage.ObjectsWithName, into its account, container, and blob name.
+func splitAzureBlobURI(uri string) (account, container, blob string, err error) {
+	trimmed := strings.TrimPrefix(uri, "azurerm://")
+	parts := strings.SplitN(trimmed, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid azurerm blob URI, want azurerm://<account>/<container>/<blob>, got: %s", uri)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// TerraformCloudStorage implements StateBackend for workspaces managed by Terraform
+// Cloud/HCP, fetching state via the state-versions API rather than an object store.
+type TerraformCloudStorage struct {
+	Organization string
+	HostURL      string
+	Token        string
+
+	httpClient *http.Client
+}
+
+// NewTerraformCloudStorage creates a client for the given tfc://org/workspace backend URI,
+// authenticating with the API token in the TFE_TOKEN environment variable.
+func NewTerraformCloudStorage(ctx context.Context, backendURI string) (*TerraformCloudStorage, error) {
+	org, _, err := SplitObjectURI(backendURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tfc backend URI, want tfc://org/workspace: %w", err)
+	}
+	return &TerraformCloudStorage{
+		Organization: *org,
+		HostURL:      "https://app.terraform.io",
+		Token:        os.Getenv("TFE_TOKEN"),
+		httpClient:   http.DefaultClient,
+	}, nil
+}
+
+// ObjectsWithName returns the single tfc:// URI for the current state version of the given
+// workspace. objectName is ignored; TFC workspaces have exactly one current state version.
+func (t *TerraformCloudStorage) ObjectsWithName(ctx context.Context, workspace, objectName string) ([]string, error) {
+	return []string{fmt.Sprintf("tfc://%s/%s", t.Organization, workspace)}, nil
+}
+
+// DownloadObjectURI downloads the current state version for the workspace named in a
+// tfc://<org>/<workspace> URI previously returned by ObjectsWithName, via the Terraform Cloud
+// state-versions API: https://developer.hashicorp.com/terraform/cloud-docs/api-docs/state-versions.
+func (t *TerraformCloudStorage) DownloadObjectURI(ctx context.Context, uri string) (io.ReadCloser, error) {
+	_, workspace, err := SplitObjectURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tfc URI: %w", err)
+	}
+
+	workspaceID, err := t.workspaceID(ctx, *workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up tfc workspace %s/%s: %w", t.Organization, *workspace, err)
+	}
+
+	downloadURL, err := t.currentStateDownloadURL(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up current state version for workspace %s/%s: %w", t.Organization, *workspace, err)
+	}
+
+	resp, err := t.get(ctx, downloadURL, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download state for workspace %s/%s: %w", t.Organization, *workspace, err)
+	}
+	return resp.Body, nil
+}
+
+func (t *TerraformCloudStorage) workspaceID(ctx context.Context, workspace string) (string, error) {
+	var out struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	url := fmt.Sprintf("%s/api/v2/organizations/%s/workspaces/%s", t.HostURL, t.Organization, workspace)
+	if err := t.getJSON(ctx, url, &out); err != nil {
+		return "", err
+	}
+	return out.Data.ID, nil
+}
+
+func (t *TerraformCloudStorage) currentStateDownloadURL(ctx context.Context, workspaceID string) (string, error) {
+	var out struct {
+		Data struct {
+			Attributes struct {
+				HostedStateDownloadURL string `json:"hosted-state-download-url"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	url := fmt.Sprintf("%s/api/v2/workspaces/%s/current-state-version", t.HostURL, workspaceID)
+	if err := t.getJSON(ctx, url, &out); err != nil {
+		return "", err
+	}
+	return out.Data.Attributes.HostedStateDownloadURL, nil
+}
+
+func (t *TerraformCloudStorage) getJSON(ctx context.Context, url string, out any) error {
+	resp, err := t.get(ctx, url, true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
+
+func (t *TerraformCloudStorage) get(ctx context.Context, url string, authenticated bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	if authenticated {
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return resp, nil
+}
+
+// LocalStorage implements StateBackend for terraform state stored on the local filesystem,
+// useful for tests and for self-hosted setups without a remote backend.
+type LocalStorage struct {
+	Root string
+}
+
+// NewLocalStorage creates a new LocalStorage rooted at the given directory.
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{Root: root}
+}
+
+// ObjectsWithName returns the file:// URIs of all files under "<Root>/<dir>" matching objectName.
+func (l *LocalStorage) ObjectsWithName(ctx context.Context, dir, objectName string) ([]string, error) {
+	var uris []string
+	root := filepath.Join(l.Root, dir)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), objectName) {
+			uris = append(uris, "file://"+path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", root, err)
+	}
+	return uris, nil
+}
+
+// DownloadObjectURI opens the file at a file:// URI previously returned by ObjectsWithName. The
+// path is already rooted (ObjectsWithName joins it with Root), so it's opened as-is rather than
+// re-joined with Root.
+func (l *LocalStorage) DownloadObjectURI(ctx context.Context, uri string) (io.ReadCloser, error) {
+	path := strings.TrimPrefix(uri, "file://")
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLocalStorage_RoundTrip exercises ObjectsWithName -> DownloadObjectURI end-to-end against a
+// real directory, catching the double-rooting bug where DownloadObjectURI re-joined Root onto a
+// URI that ObjectsWithName had already rooted.
+func TestLocalStorage_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	envDir := filepath.Join(root, "envs", "prod")
+	if err := os.MkdirAll(envDir, 0o755); err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+	want := []byte(`{"resources":[]}`)
+	if err := os.WriteFile(filepath.Join(envDir, "default.tfstate"), want, 0o644); err != nil {
+		t.Fatalf("failed to write test state file: %v", err)
+	}
+
+	l := NewLocalStorage(root)
+	ctx := context.Background()
+
+	uris, err := l.ObjectsWithName(ctx, "envs/prod", "default.tfstate")
+	if err != nil {
+		t.Fatalf("ObjectsWithName returned error: %v", err)
+	}
+	if len(uris) != 1 {
+		t.Fatalf("ObjectsWithName returned %d URIs, want 1: %v", len(uris), uris)
+	}
+
+	r, err := l.DownloadObjectURI(ctx, uris[0])
+	if err != nil {
+		t.Fatalf("DownloadObjectURI(%q) returned error: %v", uris[0], err)
+	}
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read downloaded object: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("downloaded content = %q, want %q", got, want)
+	}
+}
+
+func TestSplitAzureBlobURI(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		uri          string
+		expAccount   string
+		expContainer string
+		expBlob      string
+		expErrSubstr string
+	}{
+		{
+			name:         "valid",
+			uri:          "azurerm://myaccount/mycontainer/envs/prod/terraform.tfstate",
+			expAccount:   "myaccount",
+			expContainer: "mycontainer",
+			expBlob:      "envs/prod/terraform.tfstate",
+		},
+		{
+			name:         "missing_blob",
+			uri:          "azurerm://myaccount/mycontainer",
+			expErrSubstr: "invalid azurerm blob URI",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			account, container, blob, err := splitAzureBlobURI(tc.uri)
+			if tc.expErrSubstr != "" {
+				if err == nil {
+					t.Fatalf("splitAzureBlobURI(%q) = nil error, want error containing %q", tc.uri, tc.expErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitAzureBlobURI(%q) unexpected error: %v", tc.uri, err)
+			}
+			if account != tc.expAccount || container != tc.expContainer || blob != tc.expBlob {
+				t.Errorf("splitAzureBlobURI(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tc.uri, account, container, blob, tc.expAccount, tc.expContainer, tc.expBlob)
+			}
+		})
+	}
+}
+
+func TestSplitAccountAndContainer(t *testing.T) {
+	t.Parallel()
+
+	account, container, err := splitAccountAndContainer("myaccount/mycontainer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account != "myaccount" || container != "mycontainer" {
+		t.Errorf("splitAccountAndContainer() = (%q, %q), want (%q, %q)", account, container, "myaccount", "mycontainer")
+	}
+
+	if _, _, err := splitAccountAndContainer("myaccount"); err == nil {
+		t.Error("splitAccountAndContainer(\"myaccount\") = nil error, want error")
+	}
+}
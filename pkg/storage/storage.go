@@ -0,0 +1,115 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage provides access to the object stores that hold terraform
+// state and plan files.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	gcs "cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// Storage defines the common functionality needed to locate and download
+// terraform state and plan objects.
+type Storage interface {
+	// ObjectsWithName returns the URIs of all objects in the given bucket matching objectName.
+	ObjectsWithName(ctx context.Context, bucket, objectName string) ([]string, error)
+	// DownloadObjectURI downloads the object at uri, a URI previously returned by
+	// ObjectsWithName. Callers don't need to split the URI into a bucket and name
+	// themselves - each Storage implementation parses it back using its own scheme's
+	// format, which isn't always a plain bucket/name pair (e.g. Azure's <account>/<container>/
+	// <blob>, or a local backend's already-rooted file:// path).
+	DownloadObjectURI(ctx context.Context, uri string) (io.ReadCloser, error)
+}
+
+// StateBackend is an alias for Storage kept for callers that locate terraform state rather
+// than plan output; the two share the same object-store contract.
+type StateBackend = Storage
+
+// GoogleCloudStorage implements Storage using the GCS client library.
+type GoogleCloudStorage struct {
+	client *gcs.Client
+}
+
+// NewGoogleCloudStorage creates a new GoogleCloudStorage client.
+func NewGoogleCloudStorage(ctx context.Context) (*GoogleCloudStorage, error) {
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+	return &GoogleCloudStorage{client: client}, nil
+}
+
+// ObjectsWithName returns the gs:// URIs of all objects in bucket whose name ends with objectName.
+func (s *GoogleCloudStorage) ObjectsWithName(ctx context.Context, bucket, objectName string) ([]string, error) {
+	var uris []string
+	it := s.client.Bucket(bucket).Objects(ctx, nil)
+	for {
+		attrs, err := it.Next()
+		if err != nil {
+			if err == iterator.Done {
+				break
+			}
+			return nil, fmt.Errorf("failed to list objects in bucket %s: %w", bucket, err)
+		}
+		if strings.HasSuffix(attrs.Name, objectName) {
+			uris = append(uris, fmt.Sprintf("gs://%s/%s", bucket, attrs.Name))
+		}
+	}
+	return uris, nil
+}
+
+// DownloadObjectURI downloads the object at a gs:// URI previously returned by ObjectsWithName.
+func (s *GoogleCloudStorage) DownloadObjectURI(ctx context.Context, uri string) (io.ReadCloser, error) {
+	bucket, name, err := SplitObjectURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gs URI: %w", err)
+	}
+
+	r, err := s.client.Bucket(*bucket).Object(*name).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object %s/%s: %w", *bucket, *name, err)
+	}
+	return r, nil
+}
+
+// SplitObjectURI splits a `<scheme>://bucket/name` URI (e.g. gs://, s3://, azurerm://, tfc://,
+// file://) into its bucket (or container/workspace) and object name.
+func SplitObjectURI(uri string) (*string, *string, error) {
+	idx := strings.Index(uri, "://")
+	if idx < 0 {
+		return nil, nil, fmt.Errorf("invalid backend URI, missing scheme: %s", uri)
+	}
+	trimmed := uri[idx+len("://"):]
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 {
+		return nil, nil, fmt.Errorf("invalid backend URI, missing object name: %s", uri)
+	}
+	return &parts[0], &parts[1], nil
+}
+
+// Scheme returns the `<scheme>` portion of a `<scheme>://...` backend URI.
+func Scheme(backendURI string) string {
+	idx := strings.Index(backendURI, "://")
+	if idx < 0 {
+		return ""
+	}
+	return backendURI[:idx]
+}
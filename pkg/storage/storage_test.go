@@ -0,0 +1,104 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitObjectURI(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		uri       string
+		expBucket string
+		expName   string
+		expErr    string
+	}{
+		{
+			name:      "gs",
+			uri:       "gs://my-bucket/path/to/state.tfstate",
+			expBucket: "my-bucket",
+			expName:   "path/to/state.tfstate",
+		},
+		{
+			name:      "tfc_org_workspace",
+			uri:       "tfc://my-org/my-workspace",
+			expBucket: "my-org",
+			expName:   "my-workspace",
+		},
+		{
+			name:   "missing_scheme",
+			uri:    "my-bucket/state.tfstate",
+			expErr: "missing scheme",
+		},
+		{
+			name:   "missing_name",
+			uri:    "gs://my-bucket",
+			expErr: "missing object name",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			bucket, name, err := SplitObjectURI(tc.uri)
+			if tc.expErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+					t.Fatalf("SplitObjectURI(%q) error = %v, want containing %q", tc.uri, err, tc.expErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitObjectURI(%q) unexpected error: %v", tc.uri, err)
+			}
+			if diff := cmp.Diff(tc.expBucket, *bucket); diff != "" {
+				t.Errorf("bucket (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expName, *name); diff != "" {
+				t.Errorf("name (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScheme(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		uri  string
+		exp  string
+	}{
+		{name: "gs", uri: "gs://bucket/name", exp: "gs"},
+		{name: "azurerm", uri: "azurerm://account/container/blob", exp: "azurerm"},
+		{name: "no_scheme", uri: "bucket/name", exp: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Scheme(tc.uri); got != tc.exp {
+				t.Errorf("Scheme(%q) = %q, want %q", tc.uri, got, tc.exp)
+			}
+		})
+	}
+}
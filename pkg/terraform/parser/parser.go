@@ -36,58 +36,85 @@ const (
 	UnknownParentType = "UNKNOWN_PARENT_TYPE"
 	// Default max size for a terraform statefile is 512 MB.
 	defaultTerraformStateFileSizeLimit = 512 * 1024 * 1024 // 512 MB
+	// defaultStateFileNamePattern is the object name Guardian looks for when a workspace doesn't
+	// configure its own, matching terraform's default local backend file name.
+	defaultStateFileNamePattern = "default.tfstate"
 )
 
+// ConditionAttribute represents the JSON terraform state `condition` block attached to an
+// IAM binding or member resource.
+type ConditionAttribute struct {
+	Title       string `json:"title"`
+	Expression  string `json:"expression"`
+	Description string `json:"description,omitempty"`
+}
+
 // ResourceInstances represents the JSON terraform state IAM instance.
 type ResourceInstance struct {
 	Attributes struct {
-		ID      string   `json:"id"`
-		Members []string `json:"members,omitempty"`
-		Member  string   `json:"member,omitempty"`
-		Folder  string   `json:"folder,omitempty"`
-		Project string   `json:"project,omitempty"`
-		Role    string   `json:"role,omitempty"`
+		ID         string              `json:"id"`
+		Members    []string            `json:"members,omitempty"`
+		Member     string              `json:"member,omitempty"`
+		Folder     string              `json:"folder,omitempty"`
+		Project    string              `json:"project,omitempty"`
+		Role       string              `json:"role,omitempty"`
+		Condition  *ConditionAttribute `json:"condition,omitempty"`
+		PolicyData string              `json:"policy_data,omitempty"`
 	}
 }
 
+// StateResource represents a single entry in a terraform state's `resources` array.
+type StateResource struct {
+	Type      string             `json:"type"`
+	Instances []ResourceInstance `json:"instances"`
+}
+
 // TerraformState represents the JSON terraform state.
 type TerraformState struct {
-	Resources []struct {
-		Type      string             `json:"type"`
-		Instances []ResourceInstance `json:"instances"`
-	} `json:"resources"`
+	Resources []StateResource `json:"resources"`
 }
 
 // Terraform defines the common terraform functionality.
 type Terraform interface {
 	// SetAssets sets the assets to use for GCP asset lookup.
 	SetAssets(gcpFolders, gcpProjects map[string]*assetinventory.HierarchyNode)
-	// StateFileURIs returns the URIs of terraform state files located in the given GCS buckets.
-	StateFileURIs(ctx context.Context, gcsBuckets []string) ([]string, error)
+	// StateFileURIs returns the URIs of terraform state files located in the given backend
+	// buckets (or containers, workspaces, directories, depending on the configured backend).
+	StateFileURIs(ctx context.Context, buckets []string) ([]string, error)
 	// ProcessStates returns the IAM permissions stored in the given state files.
-	ProcessStates(ctx context.Context, gcsUris []string) ([]*iam.AssetIAM, error)
+	ProcessStates(ctx context.Context, stateUris []string) ([]*iam.AssetIAM, error)
 }
 
 type TerraformParser struct {
-	GCS               storage.Storage
-	OrganizationID    string
+	Backend              storage.StateBackend
+	OrganizationID       string
+	StateFileNamePattern string
+	// MaxStateFileSize is the largest state file, in bytes, this parser will read. Defaults to
+	// defaultTerraformStateFileSizeLimit. Exceeding it returns an error rather than silently
+	// truncating the file.
+	MaxStateFileSize  int64
 	gcpAssetsByID     map[string]*assetinventory.HierarchyNode
 	gcpFoldersByName  map[string]*assetinventory.HierarchyNode
 	gcpProjectsByName map[string]*assetinventory.HierarchyNode
 }
 
-// NewTerraformParser creates a new terraform parser.
-func NewTerraformParser(ctx context.Context, organizationID string) (*TerraformParser, error) {
-	client, err := storage.NewGoogleCloudStorage(ctx)
+// NewTerraformParser creates a new terraform parser backed by the state backend identified by
+// backendURI (e.g. "gs://", "s3://", "azurerm://", "tfc://org/workspace", or "file://").
+// Workspaces that name their state file something other than "default.tfstate" can set
+// StateFileNamePattern on the returned parser before calling StateFileURIs.
+func NewTerraformParser(ctx context.Context, organizationID, backendURI string) (*TerraformParser, error) {
+	backend, err := storage.NewStateBackend(ctx, backendURI)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize gcs Client: %w", err)
+		return nil, fmt.Errorf("failed to initialize state backend: %w", err)
 	}
 	return &TerraformParser{
-		GCS:               client,
-		gcpAssetsByID:     make(map[string]*assetinventory.HierarchyNode),
-		gcpFoldersByName:  make(map[string]*assetinventory.HierarchyNode),
-		gcpProjectsByName: make(map[string]*assetinventory.HierarchyNode),
-		OrganizationID:    organizationID,
+		Backend:              backend,
+		gcpAssetsByID:        make(map[string]*assetinventory.HierarchyNode),
+		gcpFoldersByName:     make(map[string]*assetinventory.HierarchyNode),
+		gcpProjectsByName:    make(map[string]*assetinventory.HierarchyNode),
+		OrganizationID:       organizationID,
+		StateFileNamePattern: defaultStateFileNamePattern,
+		MaxStateFileSize:     defaultTerraformStateFileSizeLimit,
 	}, nil
 }
 
@@ -101,60 +128,164 @@ func (p *TerraformParser) SetAssets(
 	p.gcpProjectsByName = assetinventory.AssetsByName(gcpProjects)
 }
 
-// StateFileURIs finds all terraform state files in the given buckets.
-func (p *TerraformParser) StateFileURIs(ctx context.Context, gcsBuckets []string) ([]string, error) {
-	var gcsURIs []string
-	for _, bucket := range gcsBuckets {
-		allStateFiles, err := p.GCS.ObjectsWithName(ctx, bucket, "default.tfstate")
+// StateFileURIs finds all terraform state files in the given buckets (or containers,
+// workspaces, directories - whatever the configured backend calls them).
+func (p *TerraformParser) StateFileURIs(ctx context.Context, buckets []string) ([]string, error) {
+	pattern := p.StateFileNamePattern
+	if pattern == "" {
+		pattern = defaultStateFileNamePattern
+	}
+
+	var uris []string
+	for _, bucket := range buckets {
+		allStateFiles, err := p.Backend.ObjectsWithName(ctx, bucket, pattern)
 		if err != nil {
-			return nil, fmt.Errorf("failed to determine state files in GCS bucket %s: %w", bucket, err)
+			return nil, fmt.Errorf("failed to determine state files in %s: %w", bucket, err)
 		}
-		gcsURIs = append(gcsURIs, allStateFiles...)
+		uris = append(uris, allStateFiles...)
 	}
-	return gcsURIs, nil
+	return uris, nil
 }
 
-// ProcessStates finds all IAM in memberships, bindings, or policies in the given terraform state files.
-func (p *TerraformParser) ProcessStates(ctx context.Context, gcsUris []string) ([]*iam.AssetIAM, error) {
+// ProcessStates finds all IAM in memberships, bindings, or policies in the given terraform state
+// files. Each state is streamed and decoded one resource at a time so that states larger than
+// available memory (multi-GB states are common for large GCP organizations) don't need to be
+// buffered in full.
+func (p *TerraformParser) ProcessStates(ctx context.Context, stateUris []string) ([]*iam.AssetIAM, error) {
+	maxSize := p.MaxStateFileSize
+	if maxSize <= 0 {
+		maxSize = defaultTerraformStateFileSizeLimit
+	}
+
 	var iams []*iam.AssetIAM
-	for _, uri := range gcsUris {
-		var state TerraformState
-		bucket, name, err := storage.SplitObjectURI(uri)
+	for _, uri := range stateUris {
+		r, err := p.Backend.DownloadObjectURI(ctx, uri)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse GCS URI: %w", err)
-		}
-		r, err := p.GCS.DownloadObject(ctx, *bucket, *name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to download gcs URI for terraform: %w", err)
+			return nil, fmt.Errorf("failed to download state backend URI for terraform: %w", err)
 		}
 		defer r.Close()
-		lr := io.LimitReader(r, defaultTerraformStateFileSizeLimit)
-		if err := json.NewDecoder(lr).Decode(&state); err != nil {
-			return nil, fmt.Errorf("failed to decode terraform state: %w", err)
+
+		stateIAMs, err := p.streamTerraformStateIAM(&sizeLimitedReader{r: r, remaining: maxSize})
+		if err != nil {
+			return nil, fmt.Errorf("failed to process terraform state %s: %w", uri, err)
 		}
-		iams = append(iams, p.parseTerraformStateIAM(state)...)
+		iams = append(iams, stateIAMs...)
 	}
 	return iams, nil
 }
 
-func (p *TerraformParser) parseTerraformStateIAM(state TerraformState) []*iam.AssetIAM {
+// streamTerraformStateIAM decodes the `resources` array of a terraform state one resource at a
+// time via json.Decoder.Token, rather than unmarshalling the entire file into memory.
+func (p *TerraformParser) streamTerraformStateIAM(r io.Reader) ([]*iam.AssetIAM, error) {
+	dec := json.NewDecoder(r)
+	if err := seekToResourcesArray(dec); err != nil {
+		return nil, err
+	}
+
 	var iams []*iam.AssetIAM
-	for _, r := range state.Resources {
-		if strings.Contains(r.Type, "google_organization_iam_binding") {
-			iams = append(iams, p.parseIAMBindingForOrg(r.Instances)...)
-		} else if strings.Contains(r.Type, "google_folder_iam_binding") {
-			iams = append(iams, p.parseIAMBindingForFolder(r.Instances)...)
-		} else if strings.Contains(r.Type, "google_project_iam_binding") {
-			iams = append(iams, p.parseIAMBindingForProject(r.Instances)...)
+	for dec.More() {
+		var res StateResource
+		if err := dec.Decode(&res); err != nil {
+			return nil, fmt.Errorf("failed to decode terraform state resource: %w", err)
+		}
+		iams = append(iams, p.parseResourceIAM(res)...)
+	}
+	return iams, nil
+}
+
+// seekToResourcesArray advances dec past the top-level terraform state object's keys until it
+// is positioned just inside the opening `[` of the `resources` array, discarding the values of
+// any keys seen along the way (e.g. `outputs`, `lineage`, `serial`).
+func seekToResourcesArray(dec *json.Decoder) error {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to find resources array in terraform state: %w", err)
 		}
 
-		if strings.Contains(r.Type, "google_organization_iam_member") {
-			iams = append(iams, p.parseIAMMemberForOrg(r.Instances)...)
-		} else if strings.Contains(r.Type, "google_folder_iam_member") {
-			iams = append(iams, p.parseIAMMemberForFolder(r.Instances)...)
-		} else if strings.Contains(r.Type, "google_project_iam_member") {
-			iams = append(iams, p.parseIAMMemberForProject(r.Instances)...)
+		if _, ok := tok.(json.Delim); ok {
+			continue
 		}
+
+		key, ok := tok.(string)
+		if !ok {
+			continue
+		}
+		if key == "resources" {
+			break
+		}
+
+		var discard json.RawMessage
+		if err := dec.Decode(&discard); err != nil {
+			return fmt.Errorf("failed to skip terraform state key %q: %w", key, err)
+		}
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read resources array: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("expected terraform state \"resources\" to be a JSON array")
+	}
+	return nil
+}
+
+// sizeLimitedReader errors once more than `remaining` bytes have been read, instead of
+// silently truncating like io.LimitReader.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (s *sizeLimitedReader) Read(p []byte) (int, error) {
+	if s.remaining <= 0 {
+		return 0, fmt.Errorf("terraform state exceeds the configured max size")
+	}
+	if int64(len(p)) > s.remaining {
+		p = p[:s.remaining]
+	}
+	n, err := s.r.Read(p)
+	s.remaining -= int64(n)
+	return n, err
+}
+
+func (p *TerraformParser) parseResourceIAM(r StateResource) []*iam.AssetIAM {
+	var iams []*iam.AssetIAM
+	if strings.Contains(r.Type, "google_organization_iam_binding") {
+		iams = append(iams, p.parseIAMBindingForOrg(r.Instances)...)
+	} else if strings.Contains(r.Type, "google_folder_iam_binding") {
+		iams = append(iams, p.parseIAMBindingForFolder(r.Instances)...)
+	} else if strings.Contains(r.Type, "google_project_iam_binding") {
+		iams = append(iams, p.parseIAMBindingForProject(r.Instances)...)
+	}
+
+	if strings.Contains(r.Type, "google_organization_iam_member") {
+		iams = append(iams, p.parseIAMMemberForOrg(r.Instances)...)
+	} else if strings.Contains(r.Type, "google_folder_iam_member") {
+		iams = append(iams, p.parseIAMMemberForFolder(r.Instances)...)
+	} else if strings.Contains(r.Type, "google_project_iam_member") {
+		iams = append(iams, p.parseIAMMemberForProject(r.Instances)...)
+	}
+
+	if strings.Contains(r.Type, "google_organization_iam_policy") {
+		iams = append(iams, p.parseIAMPolicy(r.Instances, p.OrganizationID, assetinventory.Organization)...)
+	} else if strings.Contains(r.Type, "google_folder_iam_policy") {
+		iams = append(iams, p.parseIAMPolicyForFolder(r.Instances)...)
+	} else if strings.Contains(r.Type, "google_project_iam_policy") {
+		iams = append(iams, p.parseIAMPolicyForProject(r.Instances)...)
+	}
+
+	return iams
+}
+
+// parseTerraformStateIAM parses every resource in an already-decoded terraform state. Kept for
+// callers (and tests) that have a fully materialized TerraformState; ProcessStates itself uses
+// the streaming path in streamTerraformStateIAM instead.
+func (p *TerraformParser) parseTerraformStateIAM(state TerraformState) []*iam.AssetIAM {
+	var iams []*iam.AssetIAM
+	for _, r := range state.Resources {
+		iams = append(iams, p.parseResourceIAM(r)...)
 	}
 	return iams
 }
@@ -168,6 +299,7 @@ func (p *TerraformParser) parseIAMBindingForOrg(instances []ResourceInstance) []
 				Role:         i.Attributes.Role,
 				ResourceID:   p.OrganizationID,
 				ResourceType: assetinventory.Organization,
+				Condition:    conditionFromAttribute(i.Attributes.Condition),
 			})
 		}
 	}
@@ -185,6 +317,7 @@ func (p *TerraformParser) parseIAMBindingForFolder(instances []ResourceInstance)
 				Role:         i.Attributes.Role,
 				ResourceID:   parentID,
 				ResourceType: parentType,
+				Condition:    conditionFromAttribute(i.Attributes.Condition),
 			})
 		}
 	}
@@ -201,6 +334,7 @@ func (p *TerraformParser) parseIAMBindingForProject(instances []ResourceInstance
 				Role:         i.Attributes.Role,
 				ResourceID:   parentID,
 				ResourceType: parentType,
+				Condition:    conditionFromAttribute(i.Attributes.Condition),
 			})
 		}
 	}
@@ -215,6 +349,7 @@ func (p *TerraformParser) parseIAMMemberForOrg(instances []ResourceInstance) []*
 			Role:         i.Attributes.Role,
 			ResourceID:   p.OrganizationID,
 			ResourceType: assetinventory.Organization,
+			Condition:    conditionFromAttribute(i.Attributes.Condition),
 		}
 	}
 	return iams
@@ -230,6 +365,7 @@ func (p *TerraformParser) parseIAMMemberForFolder(instances []ResourceInstance)
 			Role:         i.Attributes.Role,
 			ResourceID:   parentID,
 			ResourceType: parentType,
+			Condition:    conditionFromAttribute(i.Attributes.Condition),
 		}
 	}
 	return iams
@@ -244,6 +380,7 @@ func (p *TerraformParser) parseIAMMemberForProject(instances []ResourceInstance)
 			Role:         i.Attributes.Role,
 			ResourceID:   parentID,
 			ResourceType: parentType,
+			Condition:    conditionFromAttribute(i.Attributes.Condition),
 		}
 	}
 	return iams
@@ -273,3 +410,68 @@ func (p *TerraformParser) findGCPAsset(gcpAssetID string) *assetinventory.Hierar
 		return nil
 	}
 }
+
+// parseIAMPolicy decodes an authoritative `policy_data` attribute and emits one
+// *iam.AssetIAM per (role, member) pair for the given resource.
+func (p *TerraformParser) parseIAMPolicy(instances []ResourceInstance, resourceID, resourceType string) []*iam.AssetIAM {
+	var iams []*iam.AssetIAM
+	for _, i := range instances {
+		policy, err := decodePolicyData(i.Attributes.PolicyData)
+		if err != nil {
+			continue
+		}
+		for _, b := range policy.Bindings {
+			for _, m := range b.Members {
+				iams = append(iams, &iam.AssetIAM{
+					Member:       m,
+					Role:         b.Role,
+					ResourceID:   resourceID,
+					ResourceType: resourceType,
+					Condition:    b.Condition,
+				})
+			}
+		}
+	}
+	return iams
+}
+
+func (p *TerraformParser) parseIAMPolicyForFolder(instances []ResourceInstance) []*iam.AssetIAM {
+	var iams []*iam.AssetIAM
+	for _, i := range instances {
+		folderID := strings.TrimPrefix(i.Attributes.Folder, "folders/")
+		parentID, parentType := p.maybeFindGCPAssetIDAndType(folderID)
+		iams = append(iams, p.parseIAMPolicy([]ResourceInstance{i}, parentID, parentType)...)
+	}
+	return iams
+}
+
+func (p *TerraformParser) parseIAMPolicyForProject(instances []ResourceInstance) []*iam.AssetIAM {
+	var iams []*iam.AssetIAM
+	for _, i := range instances {
+		parentID, parentType := p.maybeFindGCPAssetIDAndType(i.Attributes.Project)
+		iams = append(iams, p.parseIAMPolicy([]ResourceInstance{i}, parentID, parentType)...)
+	}
+	return iams
+}
+
+// decodePolicyData unmarshals the JSON-encoded `policy_data` attribute into an iam.Policy.
+func decodePolicyData(policyData string) (*iam.Policy, error) {
+	var policy iam.Policy
+	if err := json.Unmarshal([]byte(policyData), &policy); err != nil {
+		return nil, fmt.Errorf("failed to decode policy_data: %w", err)
+	}
+	return &policy, nil
+}
+
+// conditionFromAttribute converts a terraform state condition block into an iam.Condition,
+// returning nil for an unconditional binding.
+func conditionFromAttribute(c *ConditionAttribute) *iam.Condition {
+	if c == nil {
+		return nil
+	}
+	return &iam.Condition{
+		Title:       c.Title,
+		Expression:  c.Expression,
+		Description: c.Description,
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/abcxyz/guardian/pkg/assetinventory"
+	"github.com/abcxyz/guardian/pkg/iam"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTerraformStateIAM_Policy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		state string
+		exp   []*iam.AssetIAM
+	}{
+		{
+			name: "organization_iam_policy",
+			state: `{
+				"resources": [{
+					"type": "google_organization_iam_policy",
+					"instances": [{
+						"attributes": {
+							"policy_data": "{\"bindings\":[{\"role\":\"roles/owner\",\"members\":[\"user:a@example.com\"]}]}"
+						}
+					}]
+				}]
+			}`,
+			exp: []*iam.AssetIAM{
+				{
+					Member:       "user:a@example.com",
+					Role:         "roles/owner",
+					ResourceID:   "test-org",
+					ResourceType: assetinventory.Organization,
+				},
+			},
+		},
+		{
+			name: "folder_iam_policy",
+			state: `{
+				"resources": [{
+					"type": "google_folder_iam_policy",
+					"instances": [{
+						"attributes": {
+							"folder": "folders/123",
+							"policy_data": "{\"bindings\":[{\"role\":\"roles/editor\",\"members\":[\"user:b@example.com\"],\"condition\":{\"title\":\"expires\",\"expression\":\"request.time < timestamp(\\\"2030-01-01T00:00:00Z\\\")\"}}]}"
+						}
+					}]
+				}]
+			}`,
+			exp: []*iam.AssetIAM{
+				{
+					Member:       "user:b@example.com",
+					Role:         "roles/editor",
+					ResourceID:   "123",
+					ResourceType: assetinventory.Folder,
+					Condition: &iam.Condition{
+						Title:      "expires",
+						Expression: `request.time < timestamp("2030-01-01T00:00:00Z")`,
+					},
+				},
+			},
+		},
+		{
+			name: "project_iam_policy",
+			state: `{
+				"resources": [{
+					"type": "google_project_iam_policy",
+					"instances": [{
+						"attributes": {
+							"project": "my-project",
+							"policy_data": "{\"bindings\":[{\"role\":\"roles/viewer\",\"members\":[\"user:c@example.com\",\"user:d@example.com\"]}]}"
+						}
+					}]
+				}]
+			}`,
+			exp: []*iam.AssetIAM{
+				{
+					Member:       "user:c@example.com",
+					Role:         "roles/viewer",
+					ResourceID:   "my-project",
+					ResourceType: assetinventory.Project,
+				},
+				{
+					Member:       "user:d@example.com",
+					Role:         "roles/viewer",
+					ResourceID:   "my-project",
+					ResourceType: assetinventory.Project,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var state TerraformState
+			if err := json.Unmarshal([]byte(tc.state), &state); err != nil {
+				t.Fatalf("failed to unmarshal test state: %v", err)
+			}
+
+			p := &TerraformParser{
+				OrganizationID: "test-org",
+				gcpAssetsByID: map[string]*assetinventory.HierarchyNode{
+					"123": {ID: "123", Name: "123", NodeType: assetinventory.Folder},
+				},
+				gcpProjectsByName: map[string]*assetinventory.HierarchyNode{
+					"my-project": {ID: "my-project", Name: "my-project", NodeType: assetinventory.Project},
+				},
+			}
+
+			got := p.parseTerraformStateIAM(state)
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("parseTerraformStateIAM(%#v) got diff (-got +want):\n%s", tc.state, diff)
+			}
+		})
+	}
+}
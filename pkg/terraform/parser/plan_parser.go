@@ -0,0 +1,247 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/abcxyz/guardian/pkg/assetinventory"
+	"github.com/abcxyz/guardian/pkg/iam"
+	"github.com/abcxyz/guardian/pkg/storage"
+)
+
+// supportedPlanFormatVersions are the `terraform show -json` format_version values this
+// parser understands. See https://developer.hashicorp.com/terraform/internals/json-format.
+var supportedPlanFormatVersions = map[string]struct{}{
+	"0.1": {},
+	"0.2": {},
+	"1.0": {},
+	"1.1": {},
+}
+
+// PlanResourceChange represents a single entry in a terraform plan's `resource_changes` array.
+type PlanResourceChange struct {
+	Type   string `json:"type"`
+	Change struct {
+		Actions []string       `json:"actions"`
+		Before  map[string]any `json:"before"`
+		After   map[string]any `json:"after"`
+	} `json:"change"`
+}
+
+// TerraformPlan represents the subset of `terraform show -json` output this parser reads.
+type TerraformPlan struct {
+	FormatVersion   string               `json:"format_version"`
+	ResourceChanges []PlanResourceChange `json:"resource_changes"`
+}
+
+// Plan defines the common terraform plan functionality.
+type Plan interface {
+	// SetAssets sets the assets to use for GCP asset lookup.
+	SetAssets(gcpFolders, gcpProjects map[string]*assetinventory.HierarchyNode)
+	// ProcessPlans returns the IAM permissions proposed by the given `terraform show -json` plan files.
+	ProcessPlans(ctx context.Context, planURIs []string) ([]*iam.AssetIAM, error)
+}
+
+// PlanParser parses `terraform show -json` plan output for proposed IAM changes.
+type PlanParser struct {
+	GCS               storage.Storage
+	OrganizationID    string
+	gcpAssetsByID     map[string]*assetinventory.HierarchyNode
+	gcpFoldersByName  map[string]*assetinventory.HierarchyNode
+	gcpProjectsByName map[string]*assetinventory.HierarchyNode
+}
+
+// NewPlanParser creates a new terraform plan parser.
+func NewPlanParser(ctx context.Context, organizationID string) (*PlanParser, error) {
+	client, err := storage.NewGoogleCloudStorage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize gcs Client: %w", err)
+	}
+	return &PlanParser{
+		GCS:               client,
+		gcpAssetsByID:     make(map[string]*assetinventory.HierarchyNode),
+		gcpFoldersByName:  make(map[string]*assetinventory.HierarchyNode),
+		gcpProjectsByName: make(map[string]*assetinventory.HierarchyNode),
+		OrganizationID:    organizationID,
+	}, nil
+}
+
+// SetAssets sets up the assets to use when looking up IAM asset bindings.
+func (p *PlanParser) SetAssets(
+	gcpFolders map[string]*assetinventory.HierarchyNode,
+	gcpProjects map[string]*assetinventory.HierarchyNode,
+) {
+	p.gcpAssetsByID = assetinventory.Merge(gcpFolders, gcpProjects)
+	p.gcpFoldersByName = assetinventory.AssetsByName(gcpFolders)
+	p.gcpProjectsByName = assetinventory.AssetsByName(gcpProjects)
+}
+
+// ProcessPlans finds all proposed IAM bindings and memberships in the given
+// `terraform show -json` plan files. Authoritative `google_*_iam_policy` resource changes are
+// not parsed and yield no bindings.
+func (p *PlanParser) ProcessPlans(ctx context.Context, planURIs []string) ([]*iam.AssetIAM, error) {
+	var iams []*iam.AssetIAM
+	for _, uri := range planURIs {
+		r, err := p.GCS.DownloadObjectURI(ctx, uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download gcs URI for terraform plan: %w", err)
+		}
+		defer r.Close()
+
+		var plan TerraformPlan
+		if err := json.NewDecoder(io.LimitReader(r, defaultTerraformStateFileSizeLimit)).Decode(&plan); err != nil {
+			return nil, fmt.Errorf("failed to decode terraform plan: %w", err)
+		}
+
+		if _, ok := supportedPlanFormatVersions[plan.FormatVersion]; !ok {
+			return nil, fmt.Errorf("unsupported terraform plan format_version: %s", plan.FormatVersion)
+		}
+
+		iams = append(iams, p.parseTerraformPlanIAM(plan)...)
+	}
+	return iams, nil
+}
+
+func (p *PlanParser) parseTerraformPlanIAM(plan TerraformPlan) []*iam.AssetIAM {
+	var iams []*iam.AssetIAM
+	for _, rc := range plan.ResourceChanges {
+		action := planAction(rc.Change.Actions)
+
+		// A pure delete always reports `after: null`; the member/role/resource attributes for
+		// the binding being removed are only available in `before`.
+		attrs := rc.Change.After
+		if attrs == nil {
+			attrs = rc.Change.Before
+		}
+		if action == "" || attrs == nil {
+			continue
+		}
+
+		switch {
+		case strings.Contains(rc.Type, "google_organization_iam_binding"):
+			iams = append(iams, p.planIAMBinding(attrs, action, p.OrganizationID, assetinventory.Organization)...)
+		case strings.Contains(rc.Type, "google_organization_iam_member"):
+			iams = append(iams, p.planIAMMember(attrs, action, p.OrganizationID, assetinventory.Organization))
+		case strings.Contains(rc.Type, "google_folder_iam_binding"):
+			parentID, parentType := p.maybeFindGCPAssetIDAndType(trimResourcePrefix(stringAttr(attrs, "folder"), "folders/"))
+			iams = append(iams, p.planIAMBinding(attrs, action, parentID, parentType)...)
+		case strings.Contains(rc.Type, "google_folder_iam_member"):
+			parentID, parentType := p.maybeFindGCPAssetIDAndType(trimResourcePrefix(stringAttr(attrs, "folder"), "folders/"))
+			iams = append(iams, p.planIAMMember(attrs, action, parentID, parentType))
+		case strings.Contains(rc.Type, "google_project_iam_binding"):
+			parentID, parentType := p.maybeFindGCPAssetIDAndType(stringAttr(attrs, "project"))
+			iams = append(iams, p.planIAMBinding(attrs, action, parentID, parentType)...)
+		case strings.Contains(rc.Type, "google_project_iam_member"):
+			parentID, parentType := p.maybeFindGCPAssetIDAndType(stringAttr(attrs, "project"))
+			iams = append(iams, p.planIAMMember(attrs, action, parentID, parentType))
+		}
+	}
+	return iams
+}
+
+func (p *PlanParser) planIAMBinding(after map[string]any, action, resourceID, resourceType string) []*iam.AssetIAM {
+	role := stringAttr(after, "role")
+	var iams []*iam.AssetIAM
+	for _, m := range stringSliceAttr(after, "members") {
+		iams = append(iams, &iam.AssetIAM{
+			Member:       m,
+			Role:         role,
+			ResourceID:   resourceID,
+			ResourceType: resourceType,
+			Action:       action,
+		})
+	}
+	return iams
+}
+
+func (p *PlanParser) planIAMMember(after map[string]any, action, resourceID, resourceType string) *iam.AssetIAM {
+	return &iam.AssetIAM{
+		Member:       stringAttr(after, "member"),
+		Role:         stringAttr(after, "role"),
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		Action:       action,
+	}
+}
+
+func (p *PlanParser) maybeFindGCPAssetIDAndType(id string) (string, string) {
+	if _, ok := p.gcpFoldersByName[id]; ok {
+		return p.gcpFoldersByName[id].ID, p.gcpFoldersByName[id].NodeType
+	}
+	if _, ok := p.gcpProjectsByName[id]; ok {
+		return p.gcpProjectsByName[id].ID, p.gcpProjectsByName[id].NodeType
+	}
+	if node, ok := p.gcpAssetsByID[id]; ok {
+		return node.ID, node.NodeType
+	}
+	return UnknownParentID, UnknownParentType
+}
+
+// planAction maps a plan's change actions (e.g. ["create"], ["update"], ["delete", "create"])
+// to a single summary action. Returns "" for changes that leave the resource untouched ("no-op"
+// or "read").
+func planAction(actions []string) string {
+	switch {
+	case len(actions) == 0:
+		return ""
+	case containsString(actions, "delete") && containsString(actions, "create"):
+		return "update"
+	case containsString(actions, "delete"):
+		return "delete"
+	case containsString(actions, "create"):
+		return "create"
+	case containsString(actions, "update"):
+		return "update"
+	default:
+		return ""
+	}
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func stringAttr(m map[string]any, key string) string {
+	v, _ := m[key].(string)
+	return v
+}
+
+func stringSliceAttr(m map[string]any, key string) []string {
+	raw, ok := m[key].([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func trimResourcePrefix(s, prefix string) string {
+	return strings.TrimPrefix(s, prefix)
+}
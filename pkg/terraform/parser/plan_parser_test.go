@@ -0,0 +1,170 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/abcxyz/guardian/pkg/assetinventory"
+	"github.com/abcxyz/guardian/pkg/iam"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTerraformPlanIAM(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		plan string
+		exp  []*iam.AssetIAM
+	}{
+		{
+			name: "create_org_iam_member",
+			plan: `{
+				"resource_changes": [{
+					"type": "google_organization_iam_member",
+					"change": {
+						"actions": ["create"],
+						"before": null,
+						"after": {"role": "roles/viewer", "member": "user:a@example.com"}
+					}
+				}]
+			}`,
+			exp: []*iam.AssetIAM{
+				{
+					Member:       "user:a@example.com",
+					Role:         "roles/viewer",
+					ResourceID:   "test-org",
+					ResourceType: assetinventory.Organization,
+					Action:       "create",
+				},
+			},
+		},
+		{
+			name: "delete_org_iam_member_sourced_from_before",
+			plan: `{
+				"resource_changes": [{
+					"type": "google_organization_iam_member",
+					"change": {
+						"actions": ["delete"],
+						"before": {"role": "roles/viewer", "member": "user:a@example.com"},
+						"after": null
+					}
+				}]
+			}`,
+			exp: []*iam.AssetIAM{
+				{
+					Member:       "user:a@example.com",
+					Role:         "roles/viewer",
+					ResourceID:   "test-org",
+					ResourceType: assetinventory.Organization,
+					Action:       "delete",
+				},
+			},
+		},
+		{
+			name: "delete_project_iam_binding_sourced_from_before",
+			plan: `{
+				"resource_changes": [{
+					"type": "google_project_iam_binding",
+					"change": {
+						"actions": ["delete"],
+						"before": {"project": "my-project", "role": "roles/editor", "members": ["user:b@example.com"]},
+						"after": null
+					}
+				}]
+			}`,
+			exp: []*iam.AssetIAM{
+				{
+					Member:       "user:b@example.com",
+					Role:         "roles/editor",
+					ResourceID:   "my-project",
+					ResourceType: assetinventory.Project,
+					Action:       "delete",
+				},
+			},
+		},
+		{
+			name: "no_op_change_skipped",
+			plan: `{
+				"resource_changes": [{
+					"type": "google_project_iam_member",
+					"change": {
+						"actions": ["no-op"],
+						"before": {"project": "my-project", "role": "roles/editor", "member": "user:b@example.com"},
+						"after": {"project": "my-project", "role": "roles/editor", "member": "user:b@example.com"}
+					}
+				}]
+			}`,
+			exp: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var plan TerraformPlan
+			if err := json.Unmarshal([]byte(tc.plan), &plan); err != nil {
+				t.Fatalf("failed to unmarshal test plan: %v", err)
+			}
+
+			p := &PlanParser{
+				OrganizationID: "test-org",
+				gcpAssetsByID:  map[string]*assetinventory.HierarchyNode{},
+				gcpProjectsByName: map[string]*assetinventory.HierarchyNode{
+					"my-project": {ID: "my-project", Name: "my-project", NodeType: assetinventory.Project},
+				},
+			}
+
+			got := p.parseTerraformPlanIAM(plan)
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("parseTerraformPlanIAM(%#v) got diff (-got +want):\n%s", tc.plan, diff)
+			}
+		})
+	}
+}
+
+func TestPlanAction(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		actions []string
+		exp     string
+	}{
+		{name: "create", actions: []string{"create"}, exp: "create"},
+		{name: "update", actions: []string{"update"}, exp: "update"},
+		{name: "delete", actions: []string{"delete"}, exp: "delete"},
+		{name: "replace", actions: []string{"delete", "create"}, exp: "update"},
+		{name: "no_op", actions: []string{"no-op"}, exp: ""},
+		{name: "empty", actions: nil, exp: ""},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := planAction(tc.actions); got != tc.exp {
+				t.Errorf("planAction(%v) = %q, want %q", tc.actions, got, tc.exp)
+			}
+		})
+	}
+}
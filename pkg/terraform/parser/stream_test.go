@@ -0,0 +1,112 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// syntheticStateReader generates a terraform state JSON document on the fly containing
+// numResources google_project_iam_member resources, without ever materializing the whole
+// document in memory. Each resource is ~155 bytes, so numResources controls the total size.
+type syntheticStateReader struct {
+	numResources int
+	emitted      int
+	buf          strings.Reader
+	started      bool
+	closed       bool
+}
+
+func newSyntheticStateReader(numResources int) *syntheticStateReader {
+	s := &syntheticStateReader{numResources: numResources}
+	s.buf = *strings.NewReader(`{"version":4,"terraform_version":"1.5.0","resources":[`)
+	return s
+}
+
+func (s *syntheticStateReader) Read(p []byte) (int, error) {
+	if n, err := s.buf.Read(p); err != io.EOF {
+		return n, err
+	}
+
+	if s.emitted >= s.numResources {
+		if !s.closed {
+			s.closed = true
+			s.buf = *strings.NewReader(`]}`)
+			return s.buf.Read(p)
+		}
+		return 0, io.EOF
+	}
+
+	sep := ","
+	if s.emitted == 0 {
+		sep = ""
+	}
+	s.buf = *strings.NewReader(fmt.Sprintf(
+		`%s{"type":"google_project_iam_member","instances":[{"attributes":{"project":"project-%d","role":"roles/viewer","member":"user:u%d@example.com"}}]}`,
+		sep, s.emitted, s.emitted))
+	s.emitted++
+	return s.buf.Read(p)
+}
+
+func TestStreamTerraformStateIAM_LargeState(t *testing.T) {
+	t.Parallel()
+
+	// ~155 bytes/resource * 7,000,000 resources is over 1GB; streaming must decode it without
+	// buffering the whole document.
+	const numResources = 7_000_000
+
+	p := &TerraformParser{OrganizationID: "test-org"}
+
+	iams, err := p.streamTerraformStateIAM(newSyntheticStateReader(numResources))
+	if err != nil {
+		t.Fatalf("streamTerraformStateIAM returned error: %v", err)
+	}
+
+	if got, want := len(iams), numResources; got != want {
+		t.Errorf("got %d iam bindings, want %d", got, want)
+	}
+}
+
+func TestSizeLimitedReader(t *testing.T) {
+	t.Parallel()
+
+	r := &sizeLimitedReader{r: strings.NewReader(strings.Repeat("a", 100)), remaining: 10}
+
+	buf := make([]byte, 100)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected error on first read: %v", err)
+	}
+	if n != 10 {
+		t.Errorf("got %d bytes, want 10", n)
+	}
+
+	if _, err := r.Read(buf); err == nil {
+		t.Error("expected error once size limit is exceeded, got nil")
+	}
+}
+
+func BenchmarkStreamTerraformStateIAM(b *testing.B) {
+	p := &TerraformParser{OrganizationID: "test-org"}
+
+	for i := 0; i < b.N; i++ {
+		if _, err := p.streamTerraformStateIAM(newSyntheticStateReader(100_000)); err != nil {
+			b.Fatalf("streamTerraformStateIAM returned error: %v", err)
+		}
+	}
+}
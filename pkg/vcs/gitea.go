@@ -0,0 +1,164 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var _ Provider = (*GiteaProvider)(nil)
+
+// GiteaProvider implements Provider for runs executing in Gitea Actions, which exposes
+// GitHub-Actions-compatible environment variables alongside its own GITEA_ prefixed ones.
+type GiteaProvider struct{}
+
+// MapContext builds a PRContext from Gitea Actions environment variables.
+func (g *GiteaProvider) MapContext(env map[string]string) (*PRContext, error) {
+	repository := env["GITHUB_REPOSITORY"]
+	owner, repo, found := strings.Cut(repository, "/")
+	if !found {
+		return nil, fmt.Errorf("failed to parse gitea GITHUB_REPOSITORY %q, want owner/repo", repository)
+	}
+
+	prNumber := env["GITEA_PULL_REQUEST_NUMBER"]
+	if prNumber == "" {
+		return nil, fmt.Errorf("failed to get pull request number, GITEA_PULL_REQUEST_NUMBER is not set")
+	}
+	number, err := strconv.Atoi(prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GITEA_PULL_REQUEST_NUMBER %q: %w", prNumber, err)
+	}
+
+	var runID, runAttempt int64
+	if v := env["GITHUB_RUN_ID"]; v != "" {
+		runID, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if v := env["GITHUB_RUN_ATTEMPT"]; v != "" {
+		runAttempt, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	return &PRContext{
+		Owner:             owner,
+		Repo:              repo,
+		PullRequestNumber: number,
+		EventName:         env["GITHUB_EVENT_NAME"],
+		ServerURL:         env["GITHUB_SERVER_URL"],
+		RunID:             runID,
+		RunAttempt:        runAttempt,
+		BaseRef:           env["GITHUB_BASE_REF"],
+		HeadRef:           env["GITHUB_HEAD_REF"],
+	}, nil
+}
+
+// PostComment posts body as a comment on the pull request using the Gitea REST API.
+func (g *GiteaProvider) PostComment(ctx context.Context, prCtx *PRContext, body string) error {
+	url := fmt.Sprintf("%s/api/v1/repos/%s/%s/issues/%d/comments",
+		giteaServerURL(prCtx), prCtx.Owner, prCtx.Repo, prCtx.PullRequestNumber)
+
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("failed to marshal gitea comment body: %w", err)
+	}
+
+	resp, err := giteaDo(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post gitea comment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ListChangedFiles returns the paths of files changed in the pull request using the Gitea
+// REST API.
+func (g *GiteaProvider) ListChangedFiles(ctx context.Context, prCtx *PRContext) ([]string, error) {
+	url := fmt.Sprintf("%s/api/v1/repos/%s/%s/pulls/%d/files",
+		giteaServerURL(prCtx), prCtx.Owner, prCtx.Repo, prCtx.PullRequestNumber)
+
+	resp, err := giteaDo(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gitea pull request files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var files []struct {
+		Filename string `json:"filename"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
+		return nil, fmt.Errorf("failed to decode gitea pull request files: %w", err)
+	}
+
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		out = append(out, f.Filename)
+	}
+	return out, nil
+}
+
+// GetMergeBase returns the merge-base commit SHA using the Gitea compare API.
+func (g *GiteaProvider) GetMergeBase(ctx context.Context, prCtx *PRContext) (string, error) {
+	url := fmt.Sprintf("%s/api/v1/repos/%s/%s/compare/%s...%s",
+		giteaServerURL(prCtx), prCtx.Owner, prCtx.Repo, prCtx.BaseRef, prCtx.HeadRef)
+
+	resp, err := giteaDo(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare gitea refs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		MergeBase string `json:"merge_base"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode gitea compare response: %w", err)
+	}
+	return out.MergeBase, nil
+}
+
+// giteaServerURL returns the Gitea instance URL from the pull request context.
+func giteaServerURL(prCtx *PRContext) string {
+	return strings.TrimSuffix(prCtx.ServerURL, "/")
+}
+
+// giteaDo issues an authenticated request to the Gitea REST API using the token in the
+// GITEA_TOKEN environment variable.
+func giteaDo(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	req.Header.Set("Authorization", "token "+os.Getenv("GITEA_TOKEN"))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", url, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return resp, nil
+}
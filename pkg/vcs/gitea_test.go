@@ -0,0 +1,130 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestGiteaProvider_PostComment(t *testing.T) {
+	var gotMethod, gotPath, gotAuth string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GITEA_TOKEN", "test-token")
+
+	prCtx := &PRContext{Owner: "my-org", Repo: "my-repo", PullRequestNumber: 5, ServerURL: srv.URL}
+
+	provider := &GiteaProvider{}
+	if err := provider.PostComment(context.Background(), prCtx, "hello"); err != nil {
+		t.Fatalf("PostComment() unexpected error: %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %q, want %q", gotMethod, http.MethodPost)
+	}
+	if want := "/api/v1/repos/my-org/my-repo/issues/5/comments"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	if want := "token test-token"; gotAuth != want {
+		t.Errorf("Authorization = %q, want %q", gotAuth, want)
+	}
+}
+
+func TestGiteaServerURL_TrimsTrailingSlash(t *testing.T) {
+	t.Parallel()
+
+	if got, want := giteaServerURL(&PRContext{ServerURL: "https://gitea.example.com/"}), "https://gitea.example.com"; got != want {
+		t.Errorf("giteaServerURL() = %q, want %q", got, want)
+	}
+}
+
+func TestGiteaProvider_ListChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		files := []map[string]string{
+			{"filename": "main.tf"},
+			{"filename": "variables.tf"},
+		}
+		if err := json.NewEncoder(w).Encode(files); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	provider := &GiteaProvider{}
+	files, err := provider.ListChangedFiles(context.Background(), &PRContext{Owner: "o", Repo: "r", PullRequestNumber: 1, ServerURL: srv.URL})
+	if err != nil {
+		t.Fatalf("ListChangedFiles() unexpected error: %v", err)
+	}
+	if len(files) != 2 || files[0] != "main.tf" || files[1] != "variables.tf" {
+		t.Errorf("files = %v, want [main.tf variables.tf]", files)
+	}
+}
+
+func TestGiteaProvider_GetMergeBase(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		resp := map[string]string{"merge_base": "def456"}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	provider := &GiteaProvider{}
+	sha, err := provider.GetMergeBase(context.Background(), &PRContext{Owner: "o", Repo: "r", ServerURL: srv.URL, BaseRef: "main", HeadRef: "feature"})
+	if err != nil {
+		t.Fatalf("GetMergeBase() unexpected error: %v", err)
+	}
+	if sha != "def456" {
+		t.Errorf("sha = %q, want %q", sha, "def456")
+	}
+	if want := "/api/v1/repos/o/r/compare/main...feature"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+}
+
+func TestGiteaProvider_PostComment_ErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	provider := &GiteaProvider{}
+	err := provider.PostComment(context.Background(), &PRContext{Owner: "o", Repo: "r", PullRequestNumber: 1, ServerURL: srv.URL}, "body")
+	if diff := testutil.DiffErrString(err, "unexpected status 403"); diff != "" {
+		t.Error(diff)
+	}
+}
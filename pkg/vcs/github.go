@@ -0,0 +1,172 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/sethvargo/go-githubactions"
+)
+
+// githubAPIURL is the GitHub REST API base URL. A var rather than a const so tests can point it
+// at an httptest.Server.
+var githubAPIURL = "https://api.github.com"
+
+var _ Provider = (*GitHubProvider)(nil)
+
+// GitHubProvider implements Provider for runs executing in GitHub Actions.
+type GitHubProvider struct{}
+
+// MapContext builds a PRContext from GitHub Actions environment variables (GITHUB_REPOSITORY,
+// GITHUB_EVENT_PATH, etc).
+func (g *GitHubProvider) MapContext(env map[string]string) (*PRContext, error) {
+	action := githubactions.New(githubactions.WithGetenv(func(key string) string { return env[key] }))
+	gh, err := action.Context()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load github actions context: %w", err)
+	}
+
+	owner, repo, found := strings.Cut(gh.Repository, "/")
+	if !found {
+		return nil, fmt.Errorf("failed to parse github repository %q, want owner/repo", gh.Repository)
+	}
+
+	numberRaw, ok := gh.Event["number"]
+	if !ok {
+		return nil, fmt.Errorf("failed to get pull request number from github event")
+	}
+	numberFloat, ok := numberRaw.(float64)
+	if !ok {
+		return nil, fmt.Errorf("failed to get pull request number from github event")
+	}
+	number := int(numberFloat)
+
+	return &PRContext{
+		Owner:             owner,
+		Repo:              repo,
+		PullRequestNumber: number,
+		EventName:         gh.EventName,
+		ServerURL:         gh.ServerURL,
+		RunID:             gh.RunID,
+		RunAttempt:        gh.RunAttempt,
+		BaseRef:           env["GITHUB_BASE_REF"],
+		HeadRef:           env["GITHUB_HEAD_REF"],
+	}, nil
+}
+
+// PostComment posts body as a comment on the pull request using the GitHub REST API.
+func (g *GitHubProvider) PostComment(ctx context.Context, prCtx *PRContext, body string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", githubAPIURL, prCtx.Owner, prCtx.Repo, prCtx.PullRequestNumber)
+
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("failed to marshal github comment body: %w", err)
+	}
+
+	resp, err := githubDo(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post github comment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ListChangedFiles returns the paths of files changed in the pull request using the GitHub
+// REST API.
+func (g *GitHubProvider) ListChangedFiles(ctx context.Context, prCtx *PRContext) ([]string, error) {
+	var files []string
+
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/files?per_page=100&page=%d",
+			githubAPIURL, prCtx.Owner, prCtx.Repo, prCtx.PullRequestNumber, page)
+
+		resp, err := githubDo(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list github pull request files: %w", err)
+		}
+
+		var pageFiles []struct {
+			Filename string `json:"filename"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&pageFiles)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode github pull request files: %w", err)
+		}
+
+		for _, f := range pageFiles {
+			files = append(files, f.Filename)
+		}
+
+		if len(pageFiles) < 100 {
+			break
+		}
+	}
+
+	return files, nil
+}
+
+// GetMergeBase returns the merge-base commit SHA using the GitHub compare API.
+func (g *GitHubProvider) GetMergeBase(ctx context.Context, prCtx *PRContext) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", githubAPIURL, prCtx.Owner, prCtx.Repo, prCtx.BaseRef, prCtx.HeadRef)
+
+	resp, err := githubDo(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare github refs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		MergeBaseCommit struct {
+			SHA string `json:"sha"`
+		} `json:"merge_base_commit"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode github compare response: %w", err)
+	}
+	return out.MergeBaseCommit.SHA, nil
+}
+
+// githubDo issues an authenticated request to the GitHub REST API using the token in the
+// GITHUB_TOKEN environment variable.
+func githubDo(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("GITHUB_TOKEN"))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", url, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return resp, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abcxyz/pkg/testutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+// withGitHubTestServer points githubAPIURL at an httptest.Server running handler for the
+// duration of the test, restoring the real URL on cleanup. githubAPIURL is a package-level
+// var, so tests that use this helper do not run in parallel.
+func withGitHubTestServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	orig := githubAPIURL
+	githubAPIURL = srv.URL
+	t.Cleanup(func() { githubAPIURL = orig })
+}
+
+func TestGitHubProvider_PostComment(t *testing.T) {
+	var gotMethod, gotPath, gotAuth string
+	var gotBody map[string]string
+
+	withGitHubTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	prCtx := &PRContext{Owner: "my-org", Repo: "my-repo", PullRequestNumber: 7}
+
+	provider := &GitHubProvider{}
+	if err := provider.PostComment(context.Background(), prCtx, "hello world"); err != nil {
+		t.Fatalf("PostComment() unexpected error: %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %q, want %q", gotMethod, http.MethodPost)
+	}
+	if want := "/repos/my-org/my-repo/issues/7/comments"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	if want := "Bearer test-token"; gotAuth != want {
+		t.Errorf("Authorization = %q, want %q", gotAuth, want)
+	}
+	if gotBody["body"] != "hello world" {
+		t.Errorf("body = %q, want %q", gotBody["body"], "hello world")
+	}
+}
+
+func TestGitHubProvider_PostComment_ErrorStatus(t *testing.T) {
+	withGitHubTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	provider := &GitHubProvider{}
+	err := provider.PostComment(context.Background(), &PRContext{Owner: "o", Repo: "r", PullRequestNumber: 1}, "body")
+	if diff := testutil.DiffErrString(err, "unexpected status 500"); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestGitHubProvider_ListChangedFiles_Paginates(t *testing.T) {
+	var pagesRequested []string
+
+	withGitHubTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		pagesRequested = append(pagesRequested, r.URL.Query().Get("page"))
+
+		var files []map[string]string
+		if r.URL.Query().Get("page") == "1" {
+			for i := 0; i < 100; i++ {
+				files = append(files, map[string]string{"filename": fmt.Sprintf("file-%d.tf", i)})
+			}
+		} else {
+			files = append(files, map[string]string{"filename": "last.tf"})
+		}
+
+		if err := json.NewEncoder(w).Encode(files); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	})
+
+	provider := &GitHubProvider{}
+	files, err := provider.ListChangedFiles(context.Background(), &PRContext{Owner: "o", Repo: "r", PullRequestNumber: 1})
+	if err != nil {
+		t.Fatalf("ListChangedFiles() unexpected error: %v", err)
+	}
+
+	if len(files) != 101 {
+		t.Errorf("len(files) = %d, want 101", len(files))
+	}
+	if files[100] != "last.tf" {
+		t.Errorf("files[100] = %q, want %q", files[100], "last.tf")
+	}
+	if diff := cmp.Diff([]string{"1", "2"}, pagesRequested); diff != "" {
+		t.Errorf("pages requested (-want,+got):\n%s", diff)
+	}
+}
+
+func TestGitHubProvider_GetMergeBase(t *testing.T) {
+	var gotPath string
+
+	withGitHubTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		resp := map[string]any{
+			"merge_base_commit": map[string]string{"sha": "abc123"},
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	})
+
+	provider := &GitHubProvider{}
+	sha, err := provider.GetMergeBase(context.Background(), &PRContext{Owner: "o", Repo: "r", BaseRef: "main", HeadRef: "feature"})
+	if err != nil {
+		t.Fatalf("GetMergeBase() unexpected error: %v", err)
+	}
+
+	if sha != "abc123" {
+		t.Errorf("sha = %q, want %q", sha, "abc123")
+	}
+	if want := "/repos/o/r/compare/main...feature"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var _ Provider = (*GitLabProvider)(nil)
+
+// GitLabProvider implements Provider for runs executing in GitLab CI pipelines triggered by a
+// merge request.
+type GitLabProvider struct{}
+
+// MapContext builds a PRContext from GitLab CI predefined environment variables
+// (CI_MERGE_REQUEST_IID, CI_PROJECT_PATH, etc).
+func (g *GitLabProvider) MapContext(env map[string]string) (*PRContext, error) {
+	projectPath := env["CI_PROJECT_PATH"]
+	owner, repo, found := strings.Cut(projectPath, "/")
+	if !found {
+		return nil, fmt.Errorf("failed to parse gitlab CI_PROJECT_PATH %q, want owner/repo", projectPath)
+	}
+
+	iid := env["CI_MERGE_REQUEST_IID"]
+	if iid == "" {
+		return nil, fmt.Errorf("failed to get merge request iid, CI_MERGE_REQUEST_IID is not set")
+	}
+	number, err := strconv.Atoi(iid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CI_MERGE_REQUEST_IID %q: %w", iid, err)
+	}
+
+	var runID, runAttempt int64
+	if v := env["CI_PIPELINE_ID"]; v != "" {
+		runID, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if v := env["CI_PIPELINE_IID"]; v != "" {
+		runAttempt, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	return &PRContext{
+		Owner:             owner,
+		Repo:              repo,
+		PullRequestNumber: number,
+		EventName:         "merge_request",
+		ServerURL:         env["CI_SERVER_URL"],
+		RunID:             runID,
+		RunAttempt:        runAttempt,
+		BaseRef:           env["CI_MERGE_REQUEST_TARGET_BRANCH_NAME"],
+		HeadRef:           env["CI_COMMIT_REF_NAME"],
+	}, nil
+}
+
+// PostComment posts body as a note on the merge request using the GitLab REST API.
+func (g *GitLabProvider) PostComment(ctx context.Context, prCtx *PRContext, body string) error {
+	url := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/notes",
+		gitlabAPIBase(prCtx), gitlabProjectPath(prCtx), prCtx.PullRequestNumber)
+
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("failed to marshal gitlab note body: %w", err)
+	}
+
+	resp, err := gitlabDo(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post gitlab note: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ListChangedFiles returns the paths of files changed in the merge request using the GitLab
+// REST API.
+func (g *GitLabProvider) ListChangedFiles(ctx context.Context, prCtx *PRContext) ([]string, error) {
+	url := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/changes",
+		gitlabAPIBase(prCtx), gitlabProjectPath(prCtx), prCtx.PullRequestNumber)
+
+	resp, err := gitlabDo(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gitlab merge request changes: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Changes []struct {
+			NewPath string `json:"new_path"`
+		} `json:"changes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode gitlab merge request changes: %w", err)
+	}
+
+	files := make([]string, 0, len(out.Changes))
+	for _, c := range out.Changes {
+		files = append(files, c.NewPath)
+	}
+	return files, nil
+}
+
+// GetMergeBase returns the merge-base commit SHA between the merge request's source and target
+// branches.
+func (g *GitLabProvider) GetMergeBase(ctx context.Context, prCtx *PRContext) (string, error) {
+	q := url.Values{}
+	q.Add("refs[]", prCtx.BaseRef)
+	q.Add("refs[]", prCtx.HeadRef)
+
+	reqURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/merge_base?%s",
+		gitlabAPIBase(prCtx), gitlabProjectPath(prCtx), q.Encode())
+
+	resp, err := gitlabDo(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute gitlab merge base: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode gitlab merge base response: %w", err)
+	}
+	return out.ID, nil
+}
+
+// gitlabAPIBase returns the GitLab instance URL, defaulting to gitlab.com when the CI
+// environment didn't set CI_SERVER_URL.
+func gitlabAPIBase(prCtx *PRContext) string {
+	if prCtx.ServerURL != "" {
+		return prCtx.ServerURL
+	}
+	return "https://gitlab.com"
+}
+
+// gitlabProjectPath returns the URL-encoded "owner/repo" project path GitLab's API accepts in
+// place of a numeric project ID.
+func gitlabProjectPath(prCtx *PRContext) string {
+	return url.QueryEscape(prCtx.Owner + "/" + prCtx.Repo)
+}
+
+// gitlabDo issues an authenticated request to the GitLab REST API, preferring a personal/project
+// access token in GITLAB_TOKEN and falling back to the CI job token.
+func gitlabDo(ctx context.Context, method, reqURL string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", reqURL, err)
+	}
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	} else {
+		req.Header.Set("JOB-TOKEN", os.Getenv("CI_JOB_TOKEN"))
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", reqURL, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, reqURL)
+	}
+	return resp, nil
+}
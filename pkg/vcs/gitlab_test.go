@@ -0,0 +1,135 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abcxyz/pkg/testutil"
+)
+
+func TestGitLabProvider_PostComment(t *testing.T) {
+	var gotMethod, gotPath, gotAuth string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("PRIVATE-TOKEN")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GITLAB_TOKEN", "test-token")
+
+	prCtx := &PRContext{Owner: "my-group", Repo: "my-project", PullRequestNumber: 9, ServerURL: srv.URL}
+
+	provider := &GitLabProvider{}
+	if err := provider.PostComment(context.Background(), prCtx, "hello"); err != nil {
+		t.Fatalf("PostComment() unexpected error: %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %q, want %q", gotMethod, http.MethodPost)
+	}
+	if want := "/api/v4/projects/my-group%2Fmy-project/merge_requests/9/notes"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	if gotAuth != "test-token" {
+		t.Errorf("PRIVATE-TOKEN = %q, want %q", gotAuth, "test-token")
+	}
+}
+
+func TestGitLabProvider_PostComment_JobTokenFallback(t *testing.T) {
+	var gotPrivate, gotJob string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrivate = r.Header.Get("PRIVATE-TOKEN")
+		gotJob = r.Header.Get("JOB-TOKEN")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	t.Setenv("CI_JOB_TOKEN", "job-token")
+
+	prCtx := &PRContext{Owner: "o", Repo: "r", PullRequestNumber: 1, ServerURL: srv.URL}
+
+	provider := &GitLabProvider{}
+	if err := provider.PostComment(context.Background(), prCtx, "hello"); err != nil {
+		t.Fatalf("PostComment() unexpected error: %v", err)
+	}
+
+	if gotPrivate != "" {
+		t.Errorf("PRIVATE-TOKEN = %q, want empty since GITLAB_TOKEN is unset", gotPrivate)
+	}
+	if gotJob != "job-token" {
+		t.Errorf("JOB-TOKEN = %q, want %q", gotJob, "job-token")
+	}
+}
+
+func TestGitLabAPIBase_DefaultsToGitLabCom(t *testing.T) {
+	t.Parallel()
+
+	if got, want := gitlabAPIBase(&PRContext{}), "https://gitlab.com"; got != want {
+		t.Errorf("gitlabAPIBase() = %q, want %q", got, want)
+	}
+	if got, want := gitlabAPIBase(&PRContext{ServerURL: "https://gitlab.example.com"}), "https://gitlab.example.com"; got != want {
+		t.Errorf("gitlabAPIBase() = %q, want %q", got, want)
+	}
+}
+
+func TestGitLabProvider_ListChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]any{
+			"changes": []map[string]string{
+				{"new_path": "main.tf"},
+				{"new_path": "variables.tf"},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	provider := &GitLabProvider{}
+	files, err := provider.ListChangedFiles(context.Background(), &PRContext{Owner: "o", Repo: "r", PullRequestNumber: 1, ServerURL: srv.URL})
+	if err != nil {
+		t.Fatalf("ListChangedFiles() unexpected error: %v", err)
+	}
+	if len(files) != 2 || files[0] != "main.tf" || files[1] != "variables.tf" {
+		t.Errorf("files = %v, want [main.tf variables.tf]", files)
+	}
+}
+
+func TestGitLabProvider_GetMergeBase_ErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	provider := &GitLabProvider{}
+	_, err := provider.GetMergeBase(context.Background(), &PRContext{Owner: "o", Repo: "r", ServerURL: srv.URL, BaseRef: "main", HeadRef: "feature"})
+	if diff := testutil.DiffErrString(err, "unexpected status 404"); diff != "" {
+		t.Error(diff)
+	}
+}
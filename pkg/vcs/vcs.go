@@ -0,0 +1,67 @@
+// Copyright 2023 The Authors (see AUTHORS file)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vcs abstracts over the pull/merge-request-hosting git provider a Guardian run is
+// executing under, so the rest of Guardian doesn't need to assume GitHub Actions.
+package vcs
+
+import (
+	"context"
+	"fmt"
+)
+
+// PRContext holds the provider-agnostic facts about the pull (or merge) request that triggered
+// the current run.
+type PRContext struct {
+	Owner             string
+	Repo              string
+	PullRequestNumber int
+	EventName         string
+	ServerURL         string
+	RunID             int64
+	RunAttempt        int64
+	// BaseRef and HeadRef are the destination and source branch names of the pull/merge
+	// request, used to compute a merge base when one isn't otherwise available.
+	BaseRef string
+	HeadRef string
+}
+
+// Provider defines the functionality Guardian needs from a git hosting provider's CI
+// environment.
+type Provider interface {
+	// MapContext builds a PRContext from the given CI environment variables.
+	MapContext(env map[string]string) (*PRContext, error)
+	// PostComment posts body as a comment on the pull/merge request described by prCtx.
+	PostComment(ctx context.Context, prCtx *PRContext, body string) error
+	// ListChangedFiles returns the paths of files changed in the pull/merge request.
+	ListChangedFiles(ctx context.Context, prCtx *PRContext) ([]string, error)
+	// GetMergeBase returns the merge-base commit SHA between the pull/merge request's source and
+	// destination refs.
+	GetMergeBase(ctx context.Context, prCtx *PRContext) (string, error)
+}
+
+// Detect returns the Provider matching the CI environment described by env, auto-detecting
+// between GitHub Actions, GitLab CI, and Gitea Actions.
+func Detect(env map[string]string) (Provider, error) {
+	switch {
+	case env["GITHUB_ACTIONS"] == "true" && env["GITEA_ACTIONS"] != "true":
+		return &GitHubProvider{}, nil
+	case env["GITLAB_CI"] == "true":
+		return &GitLabProvider{}, nil
+	case env["GITEA_ACTIONS"] == "true":
+		return &GiteaProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unable to detect a supported VCS provider (GitHub Actions, GitLab CI, or Gitea Actions) from the environment")
+	}
+}